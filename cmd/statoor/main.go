@@ -4,13 +4,20 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
 	"github.com/weiihann/statoor/harness"
 	"github.com/weiihann/statoor/report"
 	"github.com/weiihann/statoor/workload"
@@ -39,25 +46,44 @@ layer and comparing performance metrics.`,
 	}
 
 	root.AddCommand(newRunCmd(logger))
+	root.AddCommand(newReportCmd(logger))
+	root.AddCommand(newConformanceCmd(logger))
+	root.AddCommand(newCompareCmd(logger))
 
 	return root
 }
 
 func newRunCmd(logger *slog.Logger) *cobra.Command {
 	var (
-		accounts     int
-		contracts    int
-		maxSlots     int
-		minSlots     int
-		distribution string
-		seed         int64
-		codeSize     int
-		clients      []string
-		dbDir        string
-		workloadPath string
-		harnessesDir string
-		skipBuild    bool
-		outputJSON   bool
+		accounts          int
+		contracts         int
+		maxSlots          int
+		minSlots          int
+		distribution      string
+		seed              int64
+		codeSize          int
+		clients           []string
+		clientEndpoints   []string
+		dbDir             string
+		workloadPath      string
+		workloadFormat    string
+		warmupPath        string
+		scheme            string
+		kv                string
+		cacheMB           int
+		handles           int
+		writeBufferMB     int
+		maxOpenFiles      int
+		compression       string
+		profileDir        string
+		traceDir          string
+		harnessesDir      string
+		skipBuild         bool
+		outputJSON        bool
+		parallelism       int
+		maxProcs          int
+		trieWorkers       int
+		rssSampleInterval time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -67,19 +93,35 @@ func newRunCmd(logger *slog.Logger) *cobra.Command {
 Ethereum client harnesses, comparing state roots and performance.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runBenchmark(cmd.Context(), logger, runConfig{
-				accounts:     accounts,
-				contracts:    contracts,
-				maxSlots:     maxSlots,
-				minSlots:     minSlots,
-				distribution: distribution,
-				seed:         seed,
-				codeSize:     codeSize,
-				clients:      clients,
-				dbDir:        dbDir,
-				workloadPath: workloadPath,
-				harnessesDir: harnessesDir,
-				skipBuild:    skipBuild,
-				outputJSON:   outputJSON,
+				accounts:          accounts,
+				contracts:         contracts,
+				maxSlots:          maxSlots,
+				minSlots:          minSlots,
+				distribution:      distribution,
+				seed:              seed,
+				codeSize:          codeSize,
+				clients:           clients,
+				clientEndpoints:   clientEndpoints,
+				dbDir:             dbDir,
+				workloadPath:      workloadPath,
+				workloadFormat:    workloadFormat,
+				warmupPath:        warmupPath,
+				scheme:            scheme,
+				kv:                kv,
+				cacheMB:           cacheMB,
+				handles:           handles,
+				writeBufferMB:     writeBufferMB,
+				maxOpenFiles:      maxOpenFiles,
+				compression:       compression,
+				profileDir:        profileDir,
+				traceDir:          traceDir,
+				harnessesDir:      harnessesDir,
+				skipBuild:         skipBuild,
+				outputJSON:        outputJSON,
+				parallelism:       parallelism,
+				maxProcs:          maxProcs,
+				trieWorkers:       trieWorkers,
+				rssSampleInterval: rssSampleInterval,
 			})
 		},
 	}
@@ -101,34 +143,98 @@ Ethereum client harnesses, comparing state roots and performance.`,
 		"Average contract code size in bytes")
 	flags.StringSliceVar(&clients, "clients", nil,
 		"Clients to benchmark (e.g. geth,reth,erigon)")
+	flags.StringSliceVar(&clientEndpoints, "client-endpoint", nil,
+		"Benchmark a client over JSON-RPC against an already-running node "+
+			"instead of an embedded harness binary, as client=url pairs "+
+			"(e.g. geth=http://localhost:8545,reth=http://localhost:8546)")
 	flags.StringVar(&dbDir, "db-dir", "",
 		"Base directory for client databases")
 	flags.StringVar(&workloadPath, "workload", "",
 		"Path to pre-generated workload file (skip generation)")
+	flags.StringVar(&workloadFormat, "workload-format", workload.FormatJSONL,
+		"Wire format for a generated workload: jsonl, msgpack, or binary. "+
+			"jsonl stays human-diffable; msgpack/binary are smaller and "+
+			"faster to parse for multi-million-op workloads. Harness "+
+			"binaries only speak jsonl, so non-jsonl workloads are "+
+			"transcoded back to jsonl before a run. Ignored with --workload.")
+	flags.StringVar(&warmupPath, "warmup", "",
+		"Path to a warmup workload applied and committed before --workload "+
+			"is measured (pair with a --workload whose first op is reuse_db)")
+	flags.StringVar(&scheme, "scheme", "",
+		"Trie storage scheme to request from clients that support it "+
+			"(e.g. hash or path for geth); empty uses the client's default")
+	flags.StringVar(&kv, "kv", "",
+		"KV backend to request from clients that support choosing one "+
+			"(e.g. pebble or leveldb for geth); empty uses the client's default")
+	flags.IntVar(&cacheMB, "cache-mb", 0,
+		"KV backend cache size in MB (requires --kv)")
+	flags.IntVar(&handles, "handles", 0,
+		"KV backend open file handles (requires --kv)")
+	flags.IntVar(&writeBufferMB, "write-buffer-mb", 0,
+		"KV backend write buffer size in MB (requires --kv)")
+	flags.IntVar(&maxOpenFiles, "max-open-files", 0,
+		"KV backend max open files (requires --kv)")
+	flags.StringVar(&compression, "compression", "",
+		"KV backend compression: snappy, zstd, or none (requires --kv)")
+	flags.StringVar(&profileDir, "profile-dir", "",
+		"Directory to write per-client CPU/heap profiles and execution "+
+			"traces to (enables profiling when set)")
+	flags.StringVar(&traceDir, "trace-dir", "",
+		"Directory to write per-client structured {read,hash,commit} DB "+
+			"phase trace NDJSON to (enables DB tracing when set; see "+
+			"'statoor report trace')")
 	flags.StringVar(&harnessesDir, "harnesses-dir", "",
 		"Path to harnesses directory (default: ./harnesses)")
 	flags.BoolVar(&skipBuild, "skip-build", false,
 		"Skip building harness binaries")
 	flags.BoolVar(&outputJSON, "json", false,
 		"Output results as JSON instead of table")
+	flags.IntVar(&parallelism, "parallelism", 1,
+		"Number of clients to run concurrently (1 = sequential)")
+	flags.IntVar(&maxProcs, "max-procs-per-client", 0,
+		"Cap each harness's GOMAXPROCS (requires --parallelism > 1 to "+
+			"matter; 0 = no cap)")
+	flags.IntVar(&trieWorkers, "trie-workers", 0,
+		"Worker pool size for parallel per-account storage-root "+
+			"computation, for clients that support it (e.g. erigon); "+
+			"0 leaves the harness binary's own default in place")
+	flags.DurationVar(&rssSampleInterval, "rss-sample-interval", 0,
+		"How often to poll each harness process's resident set size "+
+			"(0 = 50ms default)")
 
 	return cmd
 }
 
 type runConfig struct {
-	accounts     int
-	contracts    int
-	maxSlots     int
-	minSlots     int
-	distribution string
-	seed         int64
-	codeSize     int
-	clients      []string
-	dbDir        string
-	workloadPath string
-	harnessesDir string
-	skipBuild    bool
-	outputJSON   bool
+	accounts          int
+	contracts         int
+	maxSlots          int
+	minSlots          int
+	distribution      string
+	seed              int64
+	codeSize          int
+	clients           []string
+	clientEndpoints   []string
+	dbDir             string
+	workloadPath      string
+	workloadFormat    string
+	warmupPath        string
+	scheme            string
+	kv                string
+	cacheMB           int
+	handles           int
+	writeBufferMB     int
+	maxOpenFiles      int
+	compression       string
+	profileDir        string
+	traceDir          string
+	harnessesDir      string
+	skipBuild         bool
+	outputJSON        bool
+	parallelism       int
+	maxProcs          int
+	trieWorkers       int
+	rssSampleInterval time.Duration
 }
 
 func runBenchmark(
@@ -164,6 +270,20 @@ func runBenchmark(
 		return fmt.Errorf("resolve harnesses dir: %w", err)
 	}
 
+	endpoints, err := parseClientEndpoints(cfg.clientEndpoints)
+	if err != nil {
+		return fmt.Errorf("parse --client-endpoint: %w", err)
+	}
+
+	for client := range endpoints {
+		if !slices.Contains(cfg.clients, client) {
+			return fmt.Errorf(
+				"--client-endpoint %q does not match any --clients entry",
+				client,
+			)
+		}
+	}
+
 	// Step 1: Generate workload (or use pre-generated file).
 	workloadPath := cfg.workloadPath
 	if workloadPath == "" {
@@ -175,10 +295,16 @@ func runBenchmark(
 		defer os.Remove(workloadPath)
 	}
 
-	// Step 2: Build harness binaries (unless --skip-build).
+	// Step 2: Build harness binaries (unless --skip-build). Clients with a
+	// --client-endpoint entry run against a live node instead, so there's
+	// no binary to build.
 	binaries := make(map[string]string, len(cfg.clients))
 
 	for _, client := range cfg.clients {
+		if _, ok := endpoints[client]; ok {
+			continue
+		}
+
 		binPath := harness.ResolveBinary(harnessesDir, client)
 
 		if !cfg.skipBuild {
@@ -201,27 +327,81 @@ func runBenchmark(
 		return fmt.Errorf("create db dir: %w", err)
 	}
 
-	// Step 4: Run each harness sequentially.
-	results := make([]harness.Result, 0, len(cfg.clients))
+	// Step 4: Run each harness, fanning out across cfg.parallelism workers.
+	// A plain sequential loop (parallelism <= 1) keeps the original
+	// dbDir/<client> layout; concurrent runs get disjoint
+	// dbDir/<client>-<runID>/ subdirectories so they don't race on the
+	// same on-disk state.
+	parallelism := cfg.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
 
-	for _, client := range cfg.clients {
-		binPath := binaries[client]
-		cmdCfg := harness.WrapCommand(client, binPath)
+	runID := time.Now().UnixNano()
+	parallel := parallelism > 1 && len(cfg.clients) > 1
 
-		runner := harness.NewRunner(
-			client, cmdCfg.Binary, cmdCfg.ExtraArgs, cmdCfg.Env, logger,
-		)
-		result, runErr := runner.Run(ctx, harness.RunConfig{
-			WorkloadPath: workloadPath,
-			DBDir:        dbDir,
-			Timeout:      30 * time.Minute,
-		})
+	results := make([]harness.Result, len(cfg.clients))
 
-		if runErr != nil {
-			return fmt.Errorf("run %s: %w", client, runErr)
-		}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(parallelism)
+
+	for i, client := range cfg.clients {
+		i, client := i, client
+
+		g.Go(func() error {
+			var runner harness.ResultRunner
+
+			if endpoint, ok := endpoints[client]; ok {
+				runner = harness.NewRPCRunner(
+					client, harness.RPCConfig{Endpoint: endpoint}, logger,
+				)
+			} else {
+				binPath := binaries[client]
+				cmdCfg := harness.WrapCommand(client, binPath)
+
+				runner = harness.NewRunner(
+					client, cmdCfg.Binary, cmdCfg.ExtraArgs, cmdCfg.Env, logger,
+				)
+			}
+
+			clientDBDir := dbDir
+			if parallel {
+				clientDBDir = filepath.Join(
+					dbDir, fmt.Sprintf("%s-%d", client, runID),
+				)
+			}
+
+			result, runErr := runner.Run(gctx, harness.RunConfig{
+				WorkloadPath:      workloadPath,
+				WarmupPath:        cfg.warmupPath,
+				Scheme:            cfg.scheme,
+				KV:                cfg.kv,
+				CacheMB:           cfg.cacheMB,
+				Handles:           cfg.handles,
+				WriteBufferMB:     cfg.writeBufferMB,
+				MaxOpenFiles:      cfg.maxOpenFiles,
+				Compression:       cfg.compression,
+				ProfileDir:        cfg.profileDir,
+				TraceDir:          cfg.traceDir,
+				DBDir:             clientDBDir,
+				MaxProcs:          cfg.maxProcs,
+				TrieWorkers:       cfg.trieWorkers,
+				RSSSampleInterval: cfg.rssSampleInterval,
+				Timeout:           30 * time.Minute,
+			})
+			if runErr != nil {
+				return fmt.Errorf("run %s: %w", client, runErr)
+			}
 
-		results = append(results, *result)
+			result.Parallel = parallel
+			results[i] = *result
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
 	}
 
 	// Step 5: Generate report.
@@ -240,6 +420,607 @@ func runBenchmark(
 	return nil
 }
 
+// parseClientEndpoints parses --client-endpoint's "client=url" pairs into
+// a lookup by client name.
+func parseClientEndpoints(pairs []string) (map[string]string, error) {
+	endpoints := make(map[string]string, len(pairs))
+
+	for _, pair := range pairs {
+		client, url, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --client-endpoint %q, want client=url", pair)
+		}
+
+		endpoints[client] = url
+	}
+
+	return endpoints, nil
+}
+
+func newReportCmd(logger *slog.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate reports from harness output",
+	}
+
+	cmd.AddCommand(newProfileCmd(logger))
+	cmd.AddCommand(newTraceCmd(logger))
+
+	return cmd
+}
+
+func newProfileCmd(logger *slog.Logger) *cobra.Command {
+	var input []string
+
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Summarize per-operation latencies from replay-mode runs",
+		Long: `Profile ingests the NDJSON latency streams produced by harnesses
+run with --replay and reports p50/p95/p99 latency per operation type,
+per client.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runProfile(cmd.Context(), logger, input)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&input, "input", nil,
+		"Replay streams as client=path pairs (e.g. geth=geth.ndjson)")
+
+	return cmd
+}
+
+func runProfile(
+	_ context.Context, _ *slog.Logger, input []string,
+) error {
+	if len(input) == 0 {
+		return fmt.Errorf(
+			"at least one stream must be specified via --input",
+		)
+	}
+
+	streams := make(map[string][]report.OpEvent, len(input))
+
+	for _, pair := range input {
+		client, path, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid --input %q, want client=path", pair)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", path, err)
+		}
+
+		events, err := report.ParseReplayStream(f)
+		f.Close()
+
+		if err != nil {
+			return fmt.Errorf("parse replay stream %s: %w", path, err)
+		}
+
+		streams[client] = events
+	}
+
+	return report.GenerateProfile(os.Stdout, streams)
+}
+
+func newTraceCmd(logger *slog.Logger) *cobra.Command {
+	var (
+		input []string
+		out   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Build a DB phase timeline from --trace-dir runs",
+		Long: `Trace ingests the NDJSON {read,hash,commit} DB phase streams
+produced by harnesses run with --trace-path (see --trace-dir in 'statoor
+run') and writes a Chrome trace-viewer JSON document, loadable at
+chrome://tracing or in Perfetto, plus a p50/p95/p99 latency and
+allocation-churn summary per phase, per client.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runTrace(cmd.Context(), logger, input, out)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&input, "input", nil,
+		"DB trace streams as client=path pairs (e.g. geth=geth/trace.ndjson)")
+	cmd.Flags().StringVar(&out, "out", "trace.json",
+		"Path to write the Chrome trace-viewer JSON document to")
+
+	return cmd
+}
+
+func runTrace(
+	_ context.Context, _ *slog.Logger, input []string, out string,
+) error {
+	if len(input) == 0 {
+		return fmt.Errorf(
+			"at least one stream must be specified via --input",
+		)
+	}
+
+	streams := make(map[string][]report.DBTraceEvent, len(input))
+
+	for _, pair := range input {
+		client, path, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid --input %q, want client=path", pair)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open %s: %w", path, err)
+		}
+
+		events, err := report.ParseTraceStream(f)
+		f.Close()
+
+		if err != nil {
+			return fmt.Errorf("parse trace stream %s: %w", path, err)
+		}
+
+		streams[client] = events
+	}
+
+	traceFile, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", out, err)
+	}
+	defer traceFile.Close()
+
+	if err := report.GenerateTrace(traceFile, streams); err != nil {
+		return fmt.Errorf("generate trace: %w", err)
+	}
+
+	return report.GenerateTraceSummary(os.Stdout, streams)
+}
+
+// newConformanceCmd builds the `conformance` subcommand, which replays a
+// pinned corpus of test vectors (see workload.WriteVector) through each
+// configured harness.Runner and fails loudly when a harness's resulting
+// state root diverges from a vector's expected_state_root.
+func newConformanceCmd(logger *slog.Logger) *cobra.Command {
+	var (
+		vectorsDir   string
+		vectorsRepo  string
+		vectorsRef   string
+		clients      []string
+		harnessesDir string
+		skipBuild    bool
+		outputJSON   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "conformance",
+		Short: "Check harnesses against a corpus of pinned test vectors",
+		Long: `Conformance replays every workload in a corpus of pinned test vectors
+through each configured client, comparing the resulting state root against
+the vector's expected_state_root manifest, and exits non-zero on any
+mismatch.
+
+The corpus is either a local directory (--vectors-dir) or, similar to how
+Filecoin pins a test-vectors git submodule, a ref fetched from a remote
+(--vectors-repo + --vectors-ref).`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runConformance(cmd.Context(), logger, conformanceConfig{
+				vectorsDir:   vectorsDir,
+				vectorsRepo:  vectorsRepo,
+				vectorsRef:   vectorsRef,
+				clients:      clients,
+				harnessesDir: harnessesDir,
+				skipBuild:    skipBuild,
+				outputJSON:   outputJSON,
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&vectorsDir, "vectors-dir", "",
+		"Directory of pinned test vectors: *.jsonl workloads each paired "+
+			"with a *.manifest.json expected-root manifest")
+	flags.StringVar(&vectorsRepo, "vectors-repo", "",
+		"Git repository to fetch the corpus from, used with --vectors-ref")
+	flags.StringVar(&vectorsRef, "vectors-ref", "",
+		"Git ref (branch, tag, or commit) to pin the corpus to, fetched "+
+			"from --vectors-repo instead of reading --vectors-dir")
+	flags.StringSliceVar(&clients, "clients", nil,
+		"Clients to check (e.g. geth,reth,erigon)")
+	flags.StringVar(&harnessesDir, "harnesses-dir", "",
+		"Path to harnesses directory (default: ./harnesses)")
+	flags.BoolVar(&skipBuild, "skip-build", false,
+		"Skip building harness binaries")
+	flags.BoolVar(&outputJSON, "json", false,
+		"Output results as JSON instead of table")
+
+	return cmd
+}
+
+type conformanceConfig struct {
+	vectorsDir   string
+	vectorsRepo  string
+	vectorsRef   string
+	clients      []string
+	harnessesDir string
+	skipBuild    bool
+	outputJSON   bool
+}
+
+// vector pairs a generated workload file with the manifest describing its
+// expected outcome.
+type vector struct {
+	name         string
+	workloadPath string
+	manifest     workload.VectorManifest
+}
+
+func runConformance(
+	ctx context.Context,
+	logger *slog.Logger,
+	cfg conformanceConfig,
+) error {
+	if cfg.vectorsDir == "" && cfg.vectorsRef == "" {
+		return fmt.Errorf("one of --vectors-dir or --vectors-ref is required")
+	}
+
+	if cfg.vectorsDir != "" && cfg.vectorsRef != "" {
+		return fmt.Errorf("--vectors-dir and --vectors-ref are mutually exclusive")
+	}
+
+	if cfg.vectorsRef != "" && cfg.vectorsRepo == "" {
+		return fmt.Errorf("--vectors-repo is required when using --vectors-ref")
+	}
+
+	if len(cfg.clients) == 0 {
+		return fmt.Errorf(
+			"at least one client must be specified via --clients",
+		)
+	}
+
+	vectorsDir := cfg.vectorsDir
+
+	if cfg.vectorsRef != "" {
+		fetchedDir, cleanup, err := fetchVectorsRef(
+			ctx, logger, cfg.vectorsRepo, cfg.vectorsRef,
+		)
+		if err != nil {
+			return fmt.Errorf("fetch vectors ref: %w", err)
+		}
+		defer cleanup()
+
+		vectorsDir = fetchedDir
+	}
+
+	vectors, err := loadVectors(vectorsDir)
+	if err != nil {
+		return fmt.Errorf("load vectors: %w", err)
+	}
+
+	if len(vectors) == 0 {
+		return fmt.Errorf("no vectors found in %s", vectorsDir)
+	}
+
+	logger.InfoContext(ctx, "starting conformance check",
+		slog.Int("vectors", len(vectors)),
+		slog.Any("clients", cfg.clients),
+	)
+
+	harnessesDir := cfg.harnessesDir
+	if harnessesDir == "" {
+		harnessesDir = "harnesses"
+	}
+
+	harnessesDir, err = filepath.Abs(harnessesDir)
+	if err != nil {
+		return fmt.Errorf("resolve harnesses dir: %w", err)
+	}
+
+	binaries := make(map[string]string, len(cfg.clients))
+
+	for _, client := range cfg.clients {
+		binPath := harness.ResolveBinary(harnessesDir, client)
+
+		if !cfg.skipBuild {
+			binPath, err = harness.Build(ctx, logger, harnessesDir, client)
+			if err != nil {
+				return fmt.Errorf("build %s: %w", client, err)
+			}
+		}
+
+		binaries[client] = binPath
+	}
+
+	dbDir, err := os.MkdirTemp("", "statoor-conformance-*")
+	if err != nil {
+		return fmt.Errorf("create db dir: %w", err)
+	}
+	defer os.RemoveAll(dbDir)
+
+	var results []report.ConformanceResult
+
+	for _, vec := range vectors {
+		for _, client := range cfg.clients {
+			binPath := binaries[client]
+			cmdCfg := harness.WrapCommand(client, binPath)
+
+			runner := harness.NewRunner(
+				client, cmdCfg.Binary, cmdCfg.ExtraArgs, cmdCfg.Env, logger,
+			)
+			result, runErr := runner.Run(ctx, harness.RunConfig{
+				WorkloadPath: vec.workloadPath,
+				DBDir:        dbDir,
+				Timeout:      30 * time.Minute,
+			})
+
+			cr := report.ConformanceResult{
+				Vector:   vec.name,
+				Client:   client,
+				Expected: vec.manifest.ExpectedStateRoot,
+			}
+
+			if runErr != nil {
+				cr.Error = runErr.Error()
+			} else {
+				cr.Actual = result.StateRoot
+				cr.Pass = result.StateRoot == vec.manifest.ExpectedStateRoot
+			}
+
+			results = append(results, cr)
+		}
+	}
+
+	if cfg.outputJSON {
+		if err := report.GenerateConformanceJSON(os.Stdout, results); err != nil {
+			return fmt.Errorf("generate JSON report: %w", err)
+		}
+	} else {
+		if err := report.GenerateConformance(os.Stdout, results); err != nil {
+			return fmt.Errorf("generate report: %w", err)
+		}
+	}
+
+	for _, r := range results {
+		if r.Error != "" || !r.Pass {
+			return fmt.Errorf(
+				"conformance failed: %s/%s diverged from expected root",
+				r.Vector, r.Client,
+			)
+		}
+	}
+
+	logger.InfoContext(ctx, "conformance check complete")
+
+	return nil
+}
+
+// fetchVectorsRef shallow-clones ref from repo into a scratch directory and
+// returns its path plus a cleanup func that removes it. It shells out to git
+// the same way harness.Build shells out to the client toolchains, rather
+// than vendoring a git implementation.
+func fetchVectorsRef(
+	ctx context.Context,
+	logger *slog.Logger,
+	repo, ref string,
+) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "statoor-vectors-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("create scratch dir: %w", err)
+	}
+
+	cleanup := func() { os.RemoveAll(dir) }
+
+	logger.InfoContext(ctx, "fetching vectors",
+		slog.String("repo", repo),
+		slog.String("ref", ref),
+	)
+
+	for _, args := range [][]string{
+		{"init"},
+		{"remote", "add", "origin", repo},
+		{"fetch", "--depth", "1", "origin", ref},
+		{"checkout", "FETCH_HEAD"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+		}
+	}
+
+	return dir, cleanup, nil
+}
+
+// loadVectors reads every "<name>.jsonl" workload in dir paired with its
+// "<name>.manifest.json" sidecar, sorted by name for deterministic output.
+func loadVectors(dir string) ([]vector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var vectors []vector
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+
+		name := strings.TrimSuffix(e.Name(), ".jsonl")
+		manifestPath := filepath.Join(dir, name+".manifest.json")
+
+		raw, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("read manifest for %s: %w", name, err)
+		}
+
+		var manifest workload.VectorManifest
+		if err := json.Unmarshal(raw, &manifest); err != nil {
+			return nil, fmt.Errorf("decode manifest for %s: %w", name, err)
+		}
+
+		if manifest.Version != workload.VectorFormatVersion {
+			return nil, fmt.Errorf(
+				"vector %s: unsupported manifest version %d",
+				name, manifest.Version,
+			)
+		}
+
+		vectors = append(vectors, vector{
+			name:         name,
+			workloadPath: filepath.Join(dir, e.Name()),
+			manifest:     manifest,
+		})
+	}
+
+	sort.Slice(vectors, func(i, j int) bool {
+		return vectors[i].name < vectors[j].name
+	})
+
+	return vectors, nil
+}
+
+// newCompareCmd builds the `compare` subcommand, which runs a single
+// workload through several clients concurrently and reports whether their
+// state roots agree (see harness.Compare).
+func newCompareCmd(logger *slog.Logger) *cobra.Command {
+	var (
+		workloadPath string
+		clients      []string
+		harnessesDir string
+		skipBuild    bool
+		outputJSON   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Run a workload through multiple clients and compare state roots",
+		Long: `Compare runs --workload through every client in --clients concurrently
+and reports whether they agree on the resulting state root. When any client
+diverges from the majority, it bisects the workload to localize the first
+operation where the majority client and the first outlier's roots part ways,
+turning a root mismatch into a pointer at the offending operation instead of
+just a pass/fail.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runCompare(cmd.Context(), logger, compareConfig{
+				workloadPath: workloadPath,
+				clients:      clients,
+				harnessesDir: harnessesDir,
+				skipBuild:    skipBuild,
+				outputJSON:   outputJSON,
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&workloadPath, "workload", "",
+		"Path to the workload file to run through every client")
+	flags.StringSliceVar(&clients, "clients", nil,
+		"Clients to compare (e.g. geth,reth,erigon)")
+	flags.StringVar(&harnessesDir, "harnesses-dir", "",
+		"Path to harnesses directory (default: ./harnesses)")
+	flags.BoolVar(&skipBuild, "skip-build", false,
+		"Skip building harness binaries")
+	flags.BoolVar(&outputJSON, "json", false,
+		"Output the comparison report as JSON instead of markdown")
+
+	return cmd
+}
+
+type compareConfig struct {
+	workloadPath string
+	clients      []string
+	harnessesDir string
+	skipBuild    bool
+	outputJSON   bool
+}
+
+func runCompare(
+	ctx context.Context,
+	logger *slog.Logger,
+	cfg compareConfig,
+) error {
+	if cfg.workloadPath == "" {
+		return fmt.Errorf("--workload is required")
+	}
+
+	if len(cfg.clients) < 2 {
+		return fmt.Errorf("at least two --clients are required to compare")
+	}
+
+	logger.InfoContext(ctx, "starting comparison",
+		slog.String("workload", cfg.workloadPath),
+		slog.Any("clients", cfg.clients),
+	)
+
+	harnessesDir := cfg.harnessesDir
+	if harnessesDir == "" {
+		harnessesDir = "harnesses"
+	}
+
+	harnessesDir, err := filepath.Abs(harnessesDir)
+	if err != nil {
+		return fmt.Errorf("resolve harnesses dir: %w", err)
+	}
+
+	dbDir, err := os.MkdirTemp("", "statoor-compare-*")
+	if err != nil {
+		return fmt.Errorf("create db dir: %w", err)
+	}
+	defer os.RemoveAll(dbDir)
+
+	cfgs := make([]harness.RunConfig, 0, len(cfg.clients))
+	runners := make([]*harness.Runner, 0, len(cfg.clients))
+
+	for _, client := range cfg.clients {
+		binPath := harness.ResolveBinary(harnessesDir, client)
+
+		if !cfg.skipBuild {
+			binPath, err = harness.Build(ctx, logger, harnessesDir, client)
+			if err != nil {
+				return fmt.Errorf("build %s: %w", client, err)
+			}
+		}
+
+		cmdCfg := harness.WrapCommand(client, binPath)
+		runners = append(runners, harness.NewRunner(
+			client, cmdCfg.Binary, cmdCfg.ExtraArgs, cmdCfg.Env, logger,
+		))
+
+		cfgs = append(cfgs, harness.RunConfig{
+			WorkloadPath: cfg.workloadPath,
+			DBDir:        filepath.Join(dbDir, client),
+			Timeout:      30 * time.Minute,
+		})
+	}
+
+	comparison, err := harness.Compare(ctx, cfgs, runners)
+	if err != nil {
+		return fmt.Errorf("compare: %w", err)
+	}
+
+	if cfg.outputJSON {
+		if err := report.GenerateComparisonJSON(os.Stdout, comparison); err != nil {
+			return fmt.Errorf("generate JSON report: %w", err)
+		}
+	} else {
+		if err := report.GenerateComparison(os.Stdout, comparison); err != nil {
+			return fmt.Errorf("generate report: %w", err)
+		}
+	}
+
+	if comparison.Diverged {
+		return fmt.Errorf("clients diverged from majority root %s", comparison.MajorityRoot)
+	}
+
+	logger.InfoContext(ctx, "comparison complete")
+
+	return nil
+}
+
 func generateWorkload(
 	ctx context.Context,
 	logger *slog.Logger,
@@ -260,12 +1041,12 @@ func generateWorkload(
 		CodeSize:     cfg.codeSize,
 	})
 
-	tmpFile, err := os.CreateTemp("", "statoor-workload-*.jsonl")
+	tmpFile, err := os.CreateTemp("", "statoor-workload-*."+workloadFileExt(cfg.workloadFormat))
 	if err != nil {
 		return "", fmt.Errorf("create temp file: %w", err)
 	}
 
-	summary, err := gen.Generate(tmpFile)
+	summary, err := gen.GenerateFormat(tmpFile, cfg.workloadFormat)
 	if err != nil {
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
@@ -287,3 +1068,16 @@ func generateWorkload(
 
 	return tmpFile.Name(), nil
 }
+
+// workloadFileExt returns the file extension matching format, for naming
+// the temp file generateWorkload writes to.
+func workloadFileExt(format string) string {
+	switch format {
+	case workload.FormatMsgPack:
+		return "msgpack"
+	case workload.FormatBinary:
+		return "bin"
+	default:
+		return "jsonl"
+	}
+}
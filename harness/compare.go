@@ -0,0 +1,424 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/weiihann/statoor/workload"
+)
+
+// ComparisonReport is the result of running the same workload through
+// multiple clients and checking whether they produced the same state root.
+type ComparisonReport struct {
+	// Roots maps each client name to its reported StateRoot.
+	Roots map[string]string
+	// MajorityRoot is the StateRoot the largest group of clients agreed
+	// on, with ties broken by the lexicographically smallest root for
+	// determinism.
+	MajorityRoot string
+	// Agreement maps each client name to whether its StateRoot matched
+	// MajorityRoot.
+	Agreement map[string]bool
+	// Diverged is true when at least one client's root didn't match
+	// MajorityRoot.
+	Diverged bool
+	// Bisection localizes the first diverging operation between the
+	// majority client and the first outlier. Nil unless Diverged is true.
+	Bisection *BisectionResult
+}
+
+// BisectionResult records where two client runs' state roots first
+// diverged, found by checkpointing every operation in a single run of
+// each harness and binary-searching the resulting per-op StateRoots.
+type BisectionResult struct {
+	// Majority and Outlier are the clients being bisected: the one whose
+	// root matched ComparisonReport.MajorityRoot, and the first one that
+	// didn't.
+	Majority, Outlier string
+	// OpIndex is the 0-based index, among the ops TotalOps counts, of the
+	// first operation after which Majority and Outlier report disagreeing
+	// roots; everything before it is common ground.
+	OpIndex int
+	// TotalOps is the workload's non-terminal operation count, for
+	// context on how much of the workload the search had to cover.
+	TotalOps int
+}
+
+// Compare runs cfgs[i] through runners[i] concurrently against the same
+// workload, collects their StateRoots, and reports which clients agree
+// with the majority. When any client disagrees, it additionally bisects
+// the workload between the majority client and the first outlier to
+// localize the first diverging operation. The bisection (see
+// bisectDivergence) uses the multi-checkpoint compute_root support (see
+// harnesses/geth/main.go, harnesses/erigon/main.go) to read back every
+// op's intermediate root from a single run of each harness, rather than
+// re-running progressively longer prefixes from an empty DB: bisecting an
+// N-op workload costs one full run per harness, not log2(N).
+func Compare(ctx context.Context, cfgs []RunConfig, runners []*Runner) (*ComparisonReport, error) {
+	if len(cfgs) != len(runners) {
+		return nil, fmt.Errorf(
+			"cfgs and runners must have the same length (%d != %d)",
+			len(cfgs), len(runners),
+		)
+	}
+
+	if len(runners) == 0 {
+		return nil, fmt.Errorf("no runners to compare")
+	}
+
+	results := make([]*Result, len(runners))
+	errs := make([]error, len(runners))
+
+	var wg sync.WaitGroup
+
+	for i := range runners {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = runners[i].Run(ctx, cfgs[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("run %s: %w", runners[i].Name, err)
+		}
+	}
+
+	report := &ComparisonReport{
+		Roots:     make(map[string]string, len(runners)),
+		Agreement: make(map[string]bool, len(runners)),
+	}
+
+	rootCounts := make(map[string]int, len(runners))
+
+	for i, r := range results {
+		report.Roots[runners[i].Name] = r.StateRoot
+		rootCounts[r.StateRoot]++
+	}
+
+	for root, count := range rootCounts {
+		if count > rootCounts[report.MajorityRoot] ||
+			(count == rootCounts[report.MajorityRoot] && (report.MajorityRoot == "" || root < report.MajorityRoot)) {
+			report.MajorityRoot = root
+		}
+	}
+
+	majorityIdx, outlierIdx := -1, -1
+
+	for i, r := range results {
+		agree := r.StateRoot == report.MajorityRoot
+		report.Agreement[runners[i].Name] = agree
+
+		if !agree {
+			report.Diverged = true
+
+			if outlierIdx == -1 {
+				outlierIdx = i
+			}
+		} else if majorityIdx == -1 {
+			majorityIdx = i
+		}
+	}
+
+	if report.Diverged && majorityIdx != -1 {
+		bisection, err := bisectDivergence(
+			ctx,
+			cfgs[majorityIdx], runners[majorityIdx],
+			cfgs[outlierIdx], runners[outlierIdx],
+		)
+		if err != nil {
+			return nil, fmt.Errorf("bisect divergence: %w", err)
+		}
+
+		report.Bisection = bisection
+	}
+
+	return report, nil
+}
+
+// bisectDivergence finds the index of the first operation after which
+// runnerA and runnerB's resulting StateRoots disagree, assuming that once
+// two clients diverge they stay diverged (a coincidental root collision at
+// a later prefix is possible in principle but not something this search
+// accounts for).
+//
+// It annotates cfgA/cfgB's shared workload with a compute_root checkpoint
+// after every operation (see annotateWithCheckpoints) and runs it through
+// runnerA and runnerB once each, in fresh scratch db dirs, giving a
+// per-op StateRoot series for both sides from a single pass. firstDivergence
+// then binary-searches those two series in memory, so the only cost beyond
+// the one-time annotation is one full harness run per side, not log2(N).
+func bisectDivergence(
+	ctx context.Context,
+	cfgA RunConfig, runnerA *Runner,
+	cfgB RunConfig, runnerB *Runner,
+) (*BisectionResult, error) {
+	total, err := countOps(cfgA.WorkloadPath)
+	if err != nil {
+		return nil, fmt.Errorf("count workload ops: %w", err)
+	}
+
+	if total == 0 {
+		return &BisectionResult{
+			Majority: runnerA.Name,
+			Outlier:  runnerB.Name,
+			OpIndex:  0,
+			TotalOps: 0,
+		}, nil
+	}
+
+	annotated, cleanup, err := annotateWithCheckpoints(cfgA.WorkloadPath)
+	if err != nil {
+		return nil, fmt.Errorf("annotate workload with checkpoints: %w", err)
+	}
+	defer cleanup()
+
+	checkpointsA, checkpointsB, err := runCheckpointed(
+		ctx, annotated, cfgA, runnerA, cfgB, runnerB,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(checkpointsA) != total || len(checkpointsB) != total {
+		return nil, fmt.Errorf(
+			"checkpoint count mismatch: workload has %d ops, got %d checkpoints from %s and %d from %s",
+			total, len(checkpointsA), runnerA.Name, len(checkpointsB), runnerB.Name,
+		)
+	}
+
+	return &BisectionResult{
+		Majority: runnerA.Name,
+		Outlier:  runnerB.Name,
+		OpIndex:  firstDivergence(checkpointsA, checkpointsB),
+		TotalOps: total,
+	}, nil
+}
+
+// firstDivergence binary-searches checkpointsA/checkpointsB, two equal-length
+// per-op StateRoot series in original workload order, for the smallest index
+// at which they disagree, assuming that once they disagree they stay
+// disagreed. Returns len(checkpointsA) if every entry agrees.
+func firstDivergence(checkpointsA, checkpointsB []Checkpoint) int {
+	lo, hi := 0, len(checkpointsA)
+
+	for lo < hi {
+		mid := (lo + hi) / 2
+
+		if checkpointsA[mid].StateRoot == checkpointsB[mid].StateRoot {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo
+}
+
+// runCheckpointed runs workloadPath through runnerA and runnerB concurrently
+// in fresh scratch db dirs and returns each side's full per-op checkpoint
+// series (see foldCheckpoints).
+func runCheckpointed(
+	ctx context.Context,
+	workloadPath string,
+	cfgA RunConfig, runnerA *Runner,
+	cfgB RunConfig, runnerB *Runner,
+) ([]Checkpoint, []Checkpoint, error) {
+	runA := cfgA
+	runA.WorkloadPath = workloadPath
+
+	var err error
+
+	runA.DBDir, err = os.MkdirTemp("", "statoor-bisect-a-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create scratch db dir: %w", err)
+	}
+	defer os.RemoveAll(runA.DBDir)
+
+	runB := cfgB
+	runB.WorkloadPath = workloadPath
+
+	runB.DBDir, err = os.MkdirTemp("", "statoor-bisect-b-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create scratch db dir: %w", err)
+	}
+	defer os.RemoveAll(runB.DBDir)
+
+	var resA, resB *Result
+
+	var errA, errB error
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		resA, errA = runnerA.Run(ctx, runA)
+	}()
+
+	go func() {
+		defer wg.Done()
+		resB, errB = runnerB.Run(ctx, runB)
+	}()
+
+	wg.Wait()
+
+	if errA != nil {
+		return nil, nil, fmt.Errorf("run %s: %w", runnerA.Name, errA)
+	}
+
+	if errB != nil {
+		return nil, nil, fmt.Errorf("run %s: %w", runnerB.Name, errB)
+	}
+
+	return foldCheckpoints(resA), foldCheckpoints(resB), nil
+}
+
+// foldCheckpoints returns r's full per-op checkpoint series: r.Checkpoints
+// as parseResult filled it in (which already ends with the entry r itself
+// mirrors), or, when the harness emitted a single result and Checkpoints is
+// empty, a one-entry series built from r.
+func foldCheckpoints(r *Result) []Checkpoint {
+	if len(r.Checkpoints) > 0 {
+		return r.Checkpoints
+	}
+
+	return []Checkpoint{{
+		BlockIndex:    r.BlockIndex,
+		StateRoot:     r.StateRoot,
+		ElapsedMs:     r.ElapsedMs,
+		TrieTimeMs:    r.TrieTimeMs,
+		DBWriteTimeMs: r.DBWriteTimeMs,
+	}}
+}
+
+// countOps returns the number of operations in path that aren't themselves
+// a compute_root or replay, i.e. everything bisectDivergence can truncate
+// at. compute_root is skipped rather than treated as terminal, since
+// multi-checkpoint workloads (see workload.OpBeginBlock) use it as a
+// mid-run marker, not an end-of-workload signal; only replay is terminal.
+func countOps(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open workload %s: %w", path, err)
+	}
+	defer f.Close()
+
+	dec, err := workload.NewDecoder(f)
+	if err != nil {
+		return 0, fmt.Errorf("decode workload %s: %w", path, err)
+	}
+
+	var n int
+
+	for {
+		op, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("decode op %d: %w", n, err)
+		}
+
+		if op.Op == workload.OpReplay {
+			break
+		}
+
+		if op.Op == workload.OpComputeRoot {
+			continue
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// annotateWithCheckpoints rewrites src into a new JSONL workload that
+// inserts a synthetic compute_root checkpoint after every operation countOps
+// would count (skipping src's own compute_root markers, stopping at
+// replay), with no trailing replay of its own: a multi-checkpoint workload
+// that ends at EOF right after a checkpoint is a normal shutdown for both
+// harnesses/geth and harnesses/erigon. Running this through a harness makes
+// it emit exactly one Result per original op, in order, which
+// bisectDivergence reads back via foldCheckpoints instead of re-running the
+// harness per probe. Returns the new path and a cleanup func that removes it.
+func annotateWithCheckpoints(src string) (string, func(), error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", nil, fmt.Errorf("open workload %s: %w", src, err)
+	}
+	defer in.Close()
+
+	dec, err := workload.NewDecoder(in)
+	if err != nil {
+		return "", nil, fmt.Errorf("decode workload %s: %w", src, err)
+	}
+
+	out, err := os.CreateTemp("", "statoor-bisect-checkpoints-*.jsonl")
+	if err != nil {
+		return "", nil, fmt.Errorf("create annotated workload: %w", err)
+	}
+
+	cleanup := func() { os.Remove(out.Name()) }
+
+	enc, err := workload.NewEncoder(out, workload.FormatJSONL)
+	if err != nil {
+		out.Close()
+		cleanup()
+
+		return "", nil, fmt.Errorf("build encoder: %w", err)
+	}
+
+	var n int
+
+	for {
+		op, err := dec.Decode()
+		if err == io.EOF || op.Op == workload.OpReplay {
+			break
+		}
+
+		if err != nil {
+			out.Close()
+			cleanup()
+
+			return "", nil, fmt.Errorf("decode op %d: %w", n, err)
+		}
+
+		if op.Op == workload.OpComputeRoot {
+			continue
+		}
+
+		if err := enc.Encode(op); err != nil {
+			out.Close()
+			cleanup()
+
+			return "", nil, fmt.Errorf("write op %d: %w", n, err)
+		}
+
+		if err := enc.Encode(workload.Operation{Op: workload.OpComputeRoot}); err != nil {
+			out.Close()
+			cleanup()
+
+			return "", nil, fmt.Errorf("write checkpoint after op %d: %w", n, err)
+		}
+
+		n++
+	}
+
+	if err := out.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("close annotated workload: %w", err)
+	}
+
+	return out.Name(), cleanup, nil
+}
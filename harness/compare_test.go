@@ -0,0 +1,50 @@
+package harness
+
+import "testing"
+
+func TestFirstDivergenceAtFirstOp(t *testing.T) {
+	a := []Checkpoint{{StateRoot: "0x1"}, {StateRoot: "0x2"}, {StateRoot: "0x3"}}
+	b := []Checkpoint{{StateRoot: "0xbad1"}, {StateRoot: "0xbad2"}, {StateRoot: "0xbad3"}}
+
+	if got := firstDivergence(a, b); got != 0 {
+		t.Errorf("firstDivergence = %d, want 0", got)
+	}
+}
+
+func TestFirstDivergenceAtLastOp(t *testing.T) {
+	a := []Checkpoint{{StateRoot: "0x1"}, {StateRoot: "0x2"}, {StateRoot: "0x3"}}
+	b := []Checkpoint{{StateRoot: "0x1"}, {StateRoot: "0x2"}, {StateRoot: "0xbad"}}
+
+	if got := firstDivergence(a, b); got != 2 {
+		t.Errorf("firstDivergence = %d, want 2", got)
+	}
+}
+
+func TestFirstDivergenceNone(t *testing.T) {
+	a := []Checkpoint{{StateRoot: "0x1"}, {StateRoot: "0x2"}}
+	b := []Checkpoint{{StateRoot: "0x1"}, {StateRoot: "0x2"}}
+
+	if got := firstDivergence(a, b); got != len(a) {
+		t.Errorf("firstDivergence = %d, want %d", got, len(a))
+	}
+}
+
+func TestFoldCheckpoints(t *testing.T) {
+	r := &Result{
+		StateRoot: "0x3",
+		Checkpoints: []Checkpoint{
+			{StateRoot: "0x1"},
+			{StateRoot: "0x2"},
+			{StateRoot: "0x3"},
+		},
+	}
+
+	got := foldCheckpoints(r)
+	if len(got) != 3 {
+		t.Fatalf("len(foldCheckpoints(r)) = %d, want 3", len(got))
+	}
+
+	if got[0].StateRoot != "0x1" || got[1].StateRoot != "0x2" || got[2].StateRoot != "0x3" {
+		t.Errorf("foldCheckpoints(r) = %+v, want roots 0x1,0x2,0x3", got)
+	}
+}
@@ -1,6 +1,7 @@
 package harness
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -10,7 +11,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/weiihann/statoor/workload"
 )
 
 // RunConfig holds parameters for a single harness execution.
@@ -18,6 +24,57 @@ type RunConfig struct {
 	WorkloadPath string
 	DBDir        string
 	Timeout      time.Duration
+	// WarmupPath, if set, is applied and committed to disk before
+	// WorkloadPath is run, without wiping the db dir in between. Pair
+	// this with a WorkloadPath whose first op is "reuse_db" so the
+	// harness reopens the populated state instead of starting fresh.
+	// This measures hot-update throughput against a pre-populated trie,
+	// separate from the cold-insert cost of the warmup phase itself.
+	WarmupPath string
+	// Scheme selects the trie storage scheme passed to clients that
+	// support the distinction (e.g. "hash" or "path" for geth). Empty
+	// leaves the harness binary's own default in place.
+	Scheme string
+	// KV selects the KV backend passed to clients that support choosing
+	// one (e.g. "pebble" or "leveldb" for geth). Empty leaves the harness
+	// binary's own default in place. CacheMB, Handles, WriteBufferMB,
+	// MaxOpenFiles, and Compression are only passed through when KV is
+	// set.
+	KV            string
+	CacheMB       int
+	Handles       int
+	WriteBufferMB int
+	MaxOpenFiles  int
+	Compression   string
+	// ProfileDir, if set, enables CPU/heap/trace profiling for clients that
+	// support it (currently geth), which write their profiles under a
+	// per-client subdirectory of ProfileDir.
+	ProfileDir string
+	// TraceDir, if set, enables structured {read,hash,commit} DB phase
+	// tracing (see report.GenerateTrace) for clients that support it
+	// (currently geth), which stream their trace events to trace.ndjson
+	// under a per-client subdirectory of TraceDir.
+	TraceDir string
+	// MaxProcs, if set, caps the harness process's GOMAXPROCS via
+	// environment variable, so concurrent runs (see --parallelism in
+	// cmd/statoor) don't oversubscribe shared cores. CPU affinity pinning
+	// is not implemented; pin externally (e.g. taskset) if needed.
+	MaxProcs int
+	// TrieWorkers, if set, caps the worker pool clients that support one
+	// use for parallel per-account storage-root computation (e.g. erigon's
+	// computeStateRoot). Zero leaves the harness binary's own default
+	// (runtime.GOMAXPROCS) in place.
+	TrieWorkers int
+	// RSSSampleInterval controls how often the harness process's resident
+	// set size is polled (see sampleRSS). Zero uses defaultRSSSampleInterval.
+	RSSSampleInterval time.Duration
+}
+
+// ResultRunner is implemented by Runner and RPCRunner: anything that can
+// replay a workload and return a populated Result, so callers can dispatch
+// between the embedded-binary and live-JSON-RPC backends uniformly.
+type ResultRunner interface {
+	Run(ctx context.Context, cfg RunConfig) (*Result, error)
 }
 
 // Runner launches and manages a single harness binary.
@@ -65,23 +122,135 @@ func (r *Runner) Run(ctx context.Context, cfg RunConfig) (*Result, error) {
 		return nil, fmt.Errorf("create db dir %s: %w", dbDir, err)
 	}
 
-	args := make([]string, 0, len(r.ExtraArgs)+2)
+	if cfg.ProfileDir != "" {
+		profileDir := filepath.Join(cfg.ProfileDir, r.Name)
+
+		if err := os.MkdirAll(profileDir, 0o755); err != nil {
+			return nil, fmt.Errorf(
+				"create profile dir %s: %w", profileDir, err,
+			)
+		}
+	}
+
+	if cfg.TraceDir != "" {
+		traceDir := filepath.Join(cfg.TraceDir, r.Name)
+
+		if err := os.MkdirAll(traceDir, 0o755); err != nil {
+			return nil, fmt.Errorf(
+				"create trace dir %s: %w", traceDir, err,
+			)
+		}
+	}
+
+	var warmupElapsed time.Duration
+
+	if cfg.WarmupPath != "" {
+		warmupStart := time.Now()
+
+		if _, err := r.runOnce(ctx, cfg, cfg.WarmupPath, dbDir); err != nil {
+			return nil, fmt.Errorf("warmup %s: %w", r.Name, err)
+		}
+
+		warmupElapsed = time.Since(warmupStart)
+
+		r.Logger.Info("warmup finished",
+			slog.Duration("warmup_time", warmupElapsed),
+		)
+	}
+
+	result, err := r.runOnce(ctx, cfg, cfg.WorkloadPath, dbDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result.WarmupElapsedMs = warmupElapsed.Milliseconds()
+
+	dbSize, err := dirSize(dbDir)
+	if err != nil {
+		r.Logger.Warn("failed to measure db size",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	result.DBSizeBytes = dbSize
+
+	return result, nil
+}
+
+// runOnce runs the harness binary once against workloadPath, reusing
+// whatever state already exists in dbDir.
+func (r *Runner) runOnce(
+	ctx context.Context, cfg RunConfig, workloadPath, dbDir string,
+) (*Result, error) {
+	args := make([]string, 0, len(r.ExtraArgs)+14)
 	args = append(args, r.ExtraArgs...)
 	args = append(args, "--db", dbDir)
 
+	// --scheme, --profile-dir, --trace-path, --kv (with its tuning flags)
+	// and --trie-workers are only understood by specific harness binaries
+	// (see harnesses/geth, harnesses/erigon); passing one to a harness that
+	// doesn't define the flag would abort it at flag.Parse, so they're
+	// gated on r.Name.
+	if cfg.Scheme != "" && r.Name == "geth" {
+		args = append(args, "--scheme", cfg.Scheme)
+	}
+
+	if cfg.ProfileDir != "" && r.Name == "geth" {
+		args = append(args,
+			"--profile-dir", filepath.Join(cfg.ProfileDir, r.Name))
+	}
+
+	if cfg.TraceDir != "" && r.Name == "geth" {
+		args = append(args, "--trace-path",
+			filepath.Join(cfg.TraceDir, r.Name, "trace.ndjson"))
+	}
+
+	if cfg.TrieWorkers > 0 && r.Name == "erigon" {
+		args = append(args, "--trie-workers", strconv.Itoa(cfg.TrieWorkers))
+	}
+
+	if cfg.KV != "" && r.Name == "geth" {
+		args = append(args, "--kv", cfg.KV)
+
+		if cfg.CacheMB > 0 {
+			args = append(args, "--cache-mb", strconv.Itoa(cfg.CacheMB))
+		}
+		if cfg.Handles > 0 {
+			args = append(args, "--handles", strconv.Itoa(cfg.Handles))
+		}
+		if cfg.WriteBufferMB > 0 {
+			args = append(args,
+				"--write-buffer-mb", strconv.Itoa(cfg.WriteBufferMB))
+		}
+		if cfg.MaxOpenFiles > 0 {
+			args = append(args,
+				"--max-open-files", strconv.Itoa(cfg.MaxOpenFiles))
+		}
+		if cfg.Compression != "" {
+			args = append(args, "--compression", cfg.Compression)
+		}
+	}
+
 	cmd := exec.CommandContext(ctx, r.BinaryPath, args...)
 
-	if len(r.Env) > 0 {
-		cmd.Env = append(os.Environ(), r.Env...)
+	if len(r.Env) > 0 || cfg.MaxProcs > 0 {
+		env := os.Environ()
+
+		if cfg.MaxProcs > 0 {
+			env = append(filterEnv(env, "GOMAXPROCS"),
+				fmt.Sprintf("GOMAXPROCS=%d", cfg.MaxProcs))
+		}
+
+		cmd.Env = append(env, r.Env...)
 	}
 
-	workloadFile, err := os.Open(cfg.WorkloadPath)
+	stdin, cleanupStdin, err := openWorkloadForHarness(workloadPath)
 	if err != nil {
-		return nil, fmt.Errorf("open workload %s: %w", cfg.WorkloadPath, err)
+		return nil, err
 	}
-	defer workloadFile.Close()
+	defer cleanupStdin()
 
-	cmd.Stdin = workloadFile
+	cmd.Stdin = stdin
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -94,17 +263,46 @@ func (r *Runner) Run(ctx context.Context, cfg RunConfig) (*Result, error) {
 
 	wallStart := time.Now()
 
-	if err := cmd.Run(); err != nil {
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start harness %s: %w", r.Name, err)
+	}
+
+	sampleCtx, stopSampling := context.WithCancel(ctx)
+
+	var (
+		sampleWg          sync.WaitGroup
+		peakRSS, peakMMap uint64
+	)
+
+	sampleWg.Add(1)
+
+	go func() {
+		defer sampleWg.Done()
+		peakRSS, peakMMap = sampleRSS(sampleCtx, cmd.Process.Pid, cfg.RSSSampleInterval)
+	}()
+
+	runErr := cmd.Wait()
+	stopSampling()
+	sampleWg.Wait()
+
+	if runErr != nil {
 		return nil, fmt.Errorf(
 			"harness %s failed: %w\nstderr: %s",
-			r.Name, err, stderr.String(),
+			r.Name, runErr, stderr.String(),
 		)
 	}
 
 	wallElapsed := time.Since(wallStart)
 
+	var cpuElapsed time.Duration
+	if cmd.ProcessState != nil {
+		cpuElapsed = cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+	}
+
 	r.Logger.Info("harness finished",
 		slog.Duration("wall_time", wallElapsed),
+		slog.Duration("cpu_time", cpuElapsed),
+		slog.Uint64("peak_rss_bytes", peakRSS),
 	)
 
 	result, err := parseResult(r.Name, &stdout)
@@ -115,22 +313,241 @@ func (r *Runner) Run(ctx context.Context, cfg RunConfig) (*Result, error) {
 		)
 	}
 
-	dbSize, err := dirSize(dbDir)
+	result.WallTimeMs = wallElapsed.Milliseconds()
+	result.CPUTimeMs = cpuElapsed.Milliseconds()
+	result.PeakRSSBytes = peakRSS
+	result.MMapBytes = peakMMap
+
+	// The harness's own PeakMemoryBytes only sees its Go heap (or, for
+	// non-Go clients, whatever self-reported figure it emits), which
+	// misses mmap'd pages (e.g. MDBX's db mapping) and isn't comparable
+	// across runtimes. The externally-sampled RSS is a more meaningful
+	// cross-client number, so it takes precedence when sampling produced
+	// one; a zero means /proc was unavailable or the run ended before the
+	// first tick, so the self-reported figure is kept rather than clobbered.
+	if peakRSS > 0 {
+		result.PeakMemoryBytes = peakRSS
+	}
+
+	return result, nil
+}
+
+// defaultRSSSampleInterval is how often sampleRSS polls /proc/<pid>/status
+// when RunConfig.RSSSampleInterval is unset.
+const defaultRSSSampleInterval = 50 * time.Millisecond
+
+// sampleRSS polls /proc/<pid>/status at interval (or defaultRSSSampleInterval
+// if interval is zero) until ctx is done, tracking the peak VmRSS+VmSwap
+// (resident pages plus anything swapped out) and VmData+VmLib (mapped
+// data/lib pages, which captures memory-mapped-file-backed residency like
+// MDBX's db mapping). Sampling from /proc instead of Go's runtime.MemStats
+// is what makes these figures comparable across clients regardless of
+// runtime (Go, the JVM, .NET). Returns zeros if /proc is unavailable (e.g.
+// non-Linux) or the process exits before the first sample.
+func sampleRSS(ctx context.Context, pid int, interval time.Duration) (peakRSS, peakMMap uint64) {
+	if interval <= 0 {
+		interval = defaultRSSSampleInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return peakRSS, peakMMap
+		case <-ticker.C:
+			rss, mmap, err := readProcMemStatus(pid)
+			if err != nil {
+				continue
+			}
+
+			if rss > peakRSS {
+				peakRSS = rss
+			}
+
+			if mmap > peakMMap {
+				peakMMap = mmap
+			}
+		}
+	}
+}
+
+// readProcMemStatus reads VmRSS+VmSwap and VmData+VmLib from
+// /proc/<pid>/status, returning both in bytes.
+func readProcMemStatus(pid int) (rss, mmap uint64, err error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
 	if err != nil {
-		r.Logger.Warn("failed to measure db size",
-			slog.String("error", err.Error()),
-		)
+		return 0, 0, err
 	}
 
-	result.DBSizeBytes = dbSize
+	kb := make(map[string]uint64, 4)
 
-	return result, nil
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "VmRSS:", "VmSwap:", "VmData:", "VmLib:":
+			v, perr := strconv.ParseUint(fields[1], 10, 64)
+			if perr != nil {
+				return 0, 0, fmt.Errorf("parse %s: %w", fields[0], perr)
+			}
+
+			kb[fields[0]] = v
+		}
+	}
+
+	rss = (kb["VmRSS:"] + kb["VmSwap:"]) * 1024
+	mmap = (kb["VmData:"] + kb["VmLib:"]) * 1024
+
+	return rss, mmap, nil
+}
+
+// openWorkloadForHarness opens path for use as a harness binary's stdin.
+// Harness binaries (see harnesses/geth, harnesses/erigon) only understand
+// JSONL, so a workload written in one of workload.Encoder's more compact
+// formats (see --workload-format in cmd/statoor) is transcoded back to
+// JSONL into a temp file first; the returned cleanup removes that temp
+// file and must be called once the harness process exits. JSONL workloads
+// pass through unchanged, with no decode/re-encode overhead.
+func openWorkloadForHarness(path string) (io.Reader, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open workload %s: %w", path, err)
+	}
+
+	br := bufio.NewReaderSize(f, 1<<20)
+
+	header, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		f.Close()
+		return nil, nil, fmt.Errorf("peek workload %s: %w", path, err)
+	}
+
+	if workload.SniffFormat(header) == workload.FormatJSONL {
+		return br, func() { f.Close() }, nil
+	}
+
+	dec, err := workload.NewDecoder(br)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("read workload %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp("", "statoor-transcoded-*.jsonl")
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("create transcode temp file: %w", err)
+	}
+
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		f.Close()
+	}
+
+	enc, err := workload.NewEncoder(tmp, workload.FormatJSONL)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("create jsonl encoder: %w", err)
+	}
+
+	for {
+		op, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("decode workload %s: %w", path, err)
+		}
+
+		if err := enc.Encode(op); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("transcode workload %s: %w", path, err)
+		}
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("rewind transcoded workload: %w", err)
+	}
+
+	return tmp, cleanup, nil
 }
 
+// filterEnv returns env with any "key=..." entries removed, so a later
+// append of a fresh "key=..." entry takes effect: most getenv
+// implementations return the first match in the environment, so a stale
+// entry earlier in the slice would otherwise silently win over ours.
+func filterEnv(env []string, key string) []string {
+	prefix := key + "="
+	filtered := env[:0:0]
+
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, prefix) {
+			filtered = append(filtered, kv)
+		}
+	}
+
+	return filtered
+}
+
+// parseResult decodes a harness's stdout, which is a stream of one or more
+// result objects: a single-checkpoint run writes one, while a multi-
+// checkpoint workload (begin_block/end_block + repeated compute_root)
+// writes one per checkpoint followed by a final one. The last object
+// decoded is returned; when there was more than one, every object,
+// including that last one, is recorded in its Checkpoints so callers see a
+// per-block time-series alongside the final aggregate fields.
 func parseResult(client string, r io.Reader) (*Result, error) {
-	var result Result
-	if err := json.NewDecoder(r).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode JSON: %w", err)
+	dec := json.NewDecoder(r)
+
+	var (
+		result      Result
+		checkpoints []Checkpoint
+		found       bool
+	)
+
+	for {
+		var next Result
+		if err := dec.Decode(&next); err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return nil, fmt.Errorf("decode JSON: %w", err)
+		}
+
+		if found {
+			checkpoints = append(checkpoints, Checkpoint{
+				BlockIndex:    result.BlockIndex,
+				StateRoot:     result.StateRoot,
+				ElapsedMs:     result.ElapsedMs,
+				TrieTimeMs:    result.TrieTimeMs,
+				DBWriteTimeMs: result.DBWriteTimeMs,
+			})
+		}
+
+		result = next
+		found = true
+	}
+
+	if !found {
+		return nil, fmt.Errorf("decode JSON: %w", io.EOF)
+	}
+
+	if len(checkpoints) > 0 {
+		result.Checkpoints = append(checkpoints, Checkpoint{
+			BlockIndex:    result.BlockIndex,
+			StateRoot:     result.StateRoot,
+			ElapsedMs:     result.ElapsedMs,
+			TrieTimeMs:    result.TrieTimeMs,
+			DBWriteTimeMs: result.DBWriteTimeMs,
+		})
 	}
 
 	if result.Client == "" {
@@ -3,7 +3,11 @@ package harness
 
 // Result holds the structured output from a harness execution.
 type Result struct {
-	Client           string `json:"client"`
+	Client string `json:"client"`
+	// BlockIndex is the number of begin_block/end_block pairs a harness
+	// had processed by the time this result was emitted. Zero for harnesses
+	// or workloads that don't use multi-checkpoint blocks.
+	BlockIndex       int    `json:"block_index,omitempty"`
 	StateRoot        string `json:"state_root"`
 	AccountsCreated  int    `json:"accounts_created"`
 	ContractsCreated int    `json:"contracts_created"`
@@ -13,4 +17,81 @@ type Result struct {
 	DBWriteTimeMs    int64  `json:"db_write_time_ms"`
 	PeakMemoryBytes  uint64 `json:"peak_memory_bytes"`
 	DBSizeBytes      uint64 `json:"db_size_bytes"`
+	GasUsed          uint64 `json:"gas_used"`
+	// Scheme is the trie storage scheme used for this run: "hash" or
+	// "path". Empty for harnesses that don't support the distinction.
+	Scheme string `json:"scheme,omitempty"`
+	// WarmupElapsedMs is the time spent applying RunConfig.WarmupPath,
+	// i.e. the cold-insert cost. Zero when no warmup phase was run.
+	WarmupElapsedMs int64 `json:"warmup_elapsed_ms,omitempty"`
+	// DBConfig records the KV backend tuning used for this run, so
+	// performance differences can be attributed to DB config rather than
+	// trie algorithm.
+	DBConfig DBConfig `json:"db_config,omitempty"`
+	// CPUProfilePath, HeapProfilePath, and TracePath point to pprof/trace
+	// artifacts written under RunConfig.ProfileDir. Empty when profiling
+	// was not requested.
+	CPUProfilePath  string `json:"cpu_profile_path,omitempty"`
+	HeapProfilePath string `json:"heap_profile_path,omitempty"`
+	TracePath       string `json:"trace_path,omitempty"`
+	// DBTracePath points to the NDJSON file of structured {read,hash,commit}
+	// DB phase trace events written under RunConfig.TraceDir. Empty when
+	// tracing was not requested. See report.GenerateTrace.
+	DBTracePath string `json:"db_trace_path,omitempty"`
+	// WallTimeMs and CPUTimeMs are measured by the Runner from outside the
+	// harness process (wall-clock vs. user+sys CPU time), distinct from the
+	// harness's own self-reported ElapsedMs/TrieTimeMs/DBWriteTimeMs.
+	WallTimeMs int64 `json:"wall_time_ms,omitempty"`
+	CPUTimeMs  int64 `json:"cpu_time_ms,omitempty"`
+	// PeakRSSBytes is the harness process's peak VmRSS+VmSwap, sampled
+	// from /proc/<pid>/status while it runs (see RunConfig.RSSSampleInterval).
+	// PeakMemoryBytes is overridden with this value once sampling completes,
+	// if sampling produced a nonzero figure, since it's comparable across
+	// clients regardless of runtime, unlike a client's own self-reported
+	// figure.
+	PeakRSSBytes uint64 `json:"peak_rss_bytes,omitempty"`
+	// MMapBytes is the harness process's peak VmData+VmLib, sampled
+	// alongside PeakRSSBytes. It captures memory-mapped-file-backed
+	// residency (e.g. MDBX's db mapping) that VmRSS can undercount once
+	// the kernel has started reclaiming clean mapped pages.
+	MMapBytes uint64 `json:"mmap_bytes,omitempty"`
+	// Parallel is true when this run executed concurrently with other
+	// clients under --parallelism, so readers know peak-memory and CPU-time
+	// figures may be affected by contention for shared cores.
+	Parallel bool `json:"parallel,omitempty"`
+	// TrieParallelMs is the portion of TrieTimeMs spent computing
+	// per-account storage roots on a worker pool (see RunConfig.TrieWorkers),
+	// for clients that support the distinction (currently erigon). Zero
+	// for clients that don't report it.
+	TrieParallelMs int64 `json:"trie_parallel_ms,omitempty"`
+	// TrieWorkers is the worker count used for TrieParallelMs, for clients
+	// that support the distinction.
+	TrieWorkers int `json:"trie_workers,omitempty"`
+	// Checkpoints holds one entry per compute_root a multi-checkpoint
+	// workload's begin_block/end_block ops caused the harness to emit,
+	// in order. The rest of Result's fields (StateRoot, ElapsedMs, etc.)
+	// always mirror the last entry, so single-checkpoint consumers don't
+	// need to look at Checkpoints at all. Nil when the harness emitted a
+	// single result.
+	Checkpoints []Checkpoint `json:"checkpoints,omitempty"`
+}
+
+// Checkpoint is one block's worth of timing from a multi-checkpoint
+// workload's compute_root result line.
+type Checkpoint struct {
+	BlockIndex    int    `json:"block_index"`
+	StateRoot     string `json:"state_root"`
+	ElapsedMs     int64  `json:"elapsed_ms"`
+	TrieTimeMs    int64  `json:"trie_time_ms"`
+	DBWriteTimeMs int64  `json:"db_write_time_ms"`
+}
+
+// DBConfig holds the KV backend settings a harness was run with.
+type DBConfig struct {
+	Backend       string `json:"backend,omitempty"`
+	CacheMB       int    `json:"cache_mb,omitempty"`
+	Handles       int    `json:"handles,omitempty"`
+	WriteBufferMB int    `json:"write_buffer_mb,omitempty"`
+	MaxOpenFiles  int    `json:"max_open_files,omitempty"`
+	Compression   string `json:"compression,omitempty"`
 }
@@ -0,0 +1,527 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weiihann/statoor/workload"
+)
+
+// rpcBatchSize caps how many same-kind ops are coalesced into a single
+// JSON-RPC batch request before it's flushed.
+const rpcBatchSize = 200
+
+// RPCConfig configures an RPCRunner.
+type RPCConfig struct {
+	// Endpoint is the JSON-RPC HTTP endpoint of a live, already-running
+	// client, e.g. a geth/erigon/reth dev-mode node started with
+	// --dev --http --http.api=eth,debug,admin.
+	Endpoint string
+	// HTTPClient issues the RPC calls. A nil value uses http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// RPCRunner replays a workload against a live execution client over
+// standard JSON-RPC, instead of shelling out to an embedded harness binary
+// (see Runner). This lets users benchmark already-running geth/erigon/reth
+// dev-mode nodes without rebuilding harness binaries.
+//
+// create_account/set_code/set_storage translate to the debug_setBalance,
+// debug_setNonce, debug_setCode, and debug_setStorageAt methods dev chains
+// expose for direct state mutation, coalesced into batches per op type
+// where possible. execute_tx sends a real transaction via
+// eth_sendTransaction from the node's first unlocked account and waits for
+// its receipt. compute_root/replay read the state root off the latest
+// block header, since no standard RPC returns it on demand; per-account
+// verification via eth_getProof is left to callers that need it.
+type RPCRunner struct {
+	Name   string
+	Logger *slog.Logger
+
+	endpoint string
+	http     *http.Client
+
+	senderMu sync.Mutex
+	sender   string
+}
+
+// NewRPCRunner creates an RPCRunner for the named client.
+func NewRPCRunner(name string, cfg RPCConfig, logger *slog.Logger) *RPCRunner {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &RPCRunner{
+		Name:     name,
+		Logger:   logger.With(slog.String("client", name)),
+		endpoint: cfg.Endpoint,
+		http:     httpClient,
+	}
+}
+
+// Run replays cfg.WorkloadPath against the live client and returns parsed
+// results. Only cfg.WorkloadPath, cfg.WarmupPath and cfg.Timeout apply here; the remaining
+// RunConfig fields (DBDir, Scheme, KV tuning, ProfileDir, MaxProcs, ...)
+// describe embedded-binary concerns that don't carry over to a node
+// someone else is already running, and are ignored.
+func (r *RPCRunner) Run(ctx context.Context, cfg RunConfig) (*Result, error) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	r.Logger.Info("starting rpc run", slog.String("endpoint", r.endpoint))
+
+	var warmupElapsed time.Duration
+
+	if cfg.WarmupPath != "" {
+		warmupStart := time.Now()
+
+		// Every op in a warmup workload commits immediately against the
+		// live node, so there's no separate disk-commit step to measure
+		// the way the embedded Runner has; only the terminal op is
+		// skipped, and its root (if any) discarded.
+		if _, err := r.applyWorkload(ctx, cfg.WarmupPath, false); err != nil {
+			return nil, fmt.Errorf("warmup %s: %w", r.Name, err)
+		}
+
+		warmupElapsed = time.Since(warmupStart)
+
+		r.Logger.Info("warmup finished",
+			slog.Duration("warmup_time", warmupElapsed),
+		)
+	}
+
+	wallStart := time.Now()
+
+	stats, err := r.applyWorkload(ctx, cfg.WorkloadPath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	wallElapsed := time.Since(wallStart)
+
+	r.Logger.Info("rpc run finished",
+		slog.Duration("wall_time", wallElapsed),
+	)
+
+	return &Result{
+		Client:           r.Name,
+		StateRoot:        stats.root,
+		AccountsCreated:  stats.accounts,
+		ContractsCreated: stats.contracts,
+		StorageSlots:     stats.slots,
+		ElapsedMs:        wallElapsed.Milliseconds(),
+		GasUsed:          stats.gasUsed,
+		WallTimeMs:       wallElapsed.Milliseconds(),
+		WarmupElapsedMs:  warmupElapsed.Milliseconds(),
+	}, nil
+}
+
+// opStats accumulates the op counts and gas usage applyWorkload collects
+// while replaying a workload.
+type opStats struct {
+	accounts, contracts, slots int
+	gasUsed                    uint64
+	root                       string
+}
+
+// applyWorkload scans path and applies each op against the live node over
+// JSON-RPC. When resolveRoot is true, reaching the terminal
+// compute_root/replay op resolves and returns the state root; otherwise
+// (warmup runs) the terminal op is treated as end-of-file and its root is
+// left empty.
+func (r *RPCRunner) applyWorkload(
+	ctx context.Context, path string, resolveRoot bool,
+) (opStats, error) {
+	var stats opStats
+
+	f, err := os.Open(path)
+	if err != nil {
+		return stats, fmt.Errorf("open workload %s: %w", path, err)
+	}
+	defer f.Close()
+
+	dec, err := workload.NewDecoder(f)
+	if err != nil {
+		return stats, fmt.Errorf("read workload %s: %w", path, err)
+	}
+
+	var (
+		batch   []rpcRequest
+		batchOp string
+	)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		resps, err := r.send(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("%s batch: %w", batchOp, err)
+		}
+
+		for _, resp := range resps {
+			if resp.Error != nil {
+				return fmt.Errorf(
+					"%s batch: %s", batchOp, resp.Error.Message,
+				)
+			}
+		}
+
+		batch = batch[:0]
+
+		return nil
+	}
+
+	enqueue := func(op, method string, params ...any) error {
+		if batchOp != "" && (batchOp != op || len(batch) >= rpcBatchSize) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		batchOp = op
+		batch = append(batch, rpcRequest{
+			JSONRPC: "2.0",
+			ID:      len(batch) + 1,
+			Method:  method,
+			Params:  params,
+		})
+
+		return nil
+	}
+
+	for {
+		op, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("decode operation: %w", err)
+		}
+
+		switch op.Op {
+		case "create_account":
+			if op.Balance != "" {
+				if err := enqueue(
+					op.Op, "debug_setBalance", op.Address, op.Balance,
+				); err != nil {
+					return stats, err
+				}
+			}
+
+			if op.Nonce > 0 {
+				if err := enqueue(
+					op.Op, "debug_setNonce",
+					op.Address, fmt.Sprintf("0x%x", op.Nonce),
+				); err != nil {
+					return stats, err
+				}
+			}
+
+			stats.accounts++
+
+		case "set_code":
+			if err := enqueue(
+				op.Op, "debug_setCode", op.Address, op.Code,
+			); err != nil {
+				return stats, err
+			}
+
+			stats.contracts++
+
+		case "set_storage":
+			if err := enqueue(
+				op.Op, "debug_setStorageAt", op.Address, op.Slot, op.Value,
+			); err != nil {
+				return stats, err
+			}
+
+			stats.slots++
+
+		case workload.OpExecuteTx:
+			if err := flush(); err != nil {
+				return stats, err
+			}
+
+			used, err := r.executeTx(ctx, op)
+			if err != nil {
+				return stats, fmt.Errorf("execute_tx: %w", err)
+			}
+
+			stats.gasUsed += used
+
+		case workload.OpReuseDB:
+			// The live node keeps its own state across runs; nothing to
+			// replicate here.
+			continue
+
+		case workload.OpComputeRoot, workload.OpReplay:
+			if err := flush(); err != nil {
+				return stats, err
+			}
+
+			if !resolveRoot {
+				return stats, nil
+			}
+
+			root, err := r.stateRoot(ctx)
+			if err != nil {
+				return stats, fmt.Errorf("compute_root: %w", err)
+			}
+
+			stats.root = root
+
+			return stats, nil
+
+		default:
+			return stats, fmt.Errorf("unknown operation: %s", op.Op)
+		}
+	}
+
+	if resolveRoot {
+		return stats, fmt.Errorf(
+			"workload %s has no compute_root/replay operation", path,
+		)
+	}
+
+	if err := flush(); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// executeTx sends op as a real transaction from the node's first unlocked
+// account and returns the gas used once it's mined.
+func (r *RPCRunner) executeTx(
+	ctx context.Context, op workload.Operation,
+) (uint64, error) {
+	sender, err := r.senderAddress(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	txParams := map[string]any{
+		"from": sender,
+		"to":   op.To,
+		"data": op.Calldata,
+	}
+
+	if op.Value != "" {
+		txParams["value"] = op.Value
+	}
+
+	raw, err := r.call(ctx, "eth_sendTransaction", txParams)
+	if err != nil {
+		return 0, err
+	}
+
+	var txHash string
+	if err := json.Unmarshal(raw, &txHash); err != nil {
+		return 0, fmt.Errorf("decode tx hash: %w", err)
+	}
+
+	return r.waitForGasUsed(ctx, txHash)
+}
+
+// waitForGasUsed polls eth_getTransactionReceipt until txHash is mined and
+// returns its gasUsed. Dev-mode nodes auto-mine, so this normally resolves
+// on the first or second poll.
+func (r *RPCRunner) waitForGasUsed(ctx context.Context, txHash string) (uint64, error) {
+	for {
+		raw, err := r.call(ctx, "eth_getTransactionReceipt", txHash)
+		if err != nil {
+			return 0, err
+		}
+
+		if string(raw) != "null" {
+			var receipt struct {
+				GasUsed string `json:"gasUsed"`
+			}
+
+			if err := json.Unmarshal(raw, &receipt); err != nil {
+				return 0, fmt.Errorf("decode receipt: %w", err)
+			}
+
+			return strconv.ParseUint(
+				strings.TrimPrefix(receipt.GasUsed, "0x"), 16, 64,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// senderAddress returns the node's first unlocked account, used as the
+// origin for every execute_tx op. Cached after the first successful lookup;
+// a failed lookup (e.g. a momentary RPC hiccup) is not cached, so the next
+// execute_tx simply retries it.
+func (r *RPCRunner) senderAddress(ctx context.Context) (string, error) {
+	r.senderMu.Lock()
+	defer r.senderMu.Unlock()
+
+	if r.sender != "" {
+		return r.sender, nil
+	}
+
+	raw, err := r.call(ctx, "eth_accounts")
+	if err != nil {
+		return "", fmt.Errorf("eth_accounts: %w", err)
+	}
+
+	var accounts []string
+	if err := json.Unmarshal(raw, &accounts); err != nil {
+		return "", fmt.Errorf("decode eth_accounts: %w", err)
+	}
+
+	if len(accounts) == 0 {
+		return "", fmt.Errorf(
+			"node at %s has no unlocked accounts", r.endpoint,
+		)
+	}
+
+	r.sender = accounts[0]
+
+	return r.sender, nil
+}
+
+// stateRoot reads the state root off the latest block header.
+func (r *RPCRunner) stateRoot(ctx context.Context) (string, error) {
+	raw, err := r.call(ctx, "eth_getBlockByNumber", "latest", false)
+	if err != nil {
+		return "", err
+	}
+
+	var block struct {
+		StateRoot string `json:"stateRoot"`
+	}
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return "", fmt.Errorf("decode block: %w", err)
+	}
+
+	return block.StateRoot, nil
+}
+
+// rpcRequest is a single JSON-RPC 2.0 request object.
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+// rpcResponse is a single JSON-RPC 2.0 response object.
+type rpcResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// call issues a single JSON-RPC request and returns its result field.
+func (r *RPCRunner) call(
+	ctx context.Context, method string, params ...any,
+) (json.RawMessage, error) {
+	resps, err := r.send(ctx, []rpcRequest{{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  method,
+		Params:  params,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	if resps[0].Error != nil {
+		return nil, fmt.Errorf("%s: %s", method, resps[0].Error.Message)
+	}
+
+	return resps[0].Result, nil
+}
+
+// send posts reqs as a single JSON-RPC batch request and returns the
+// responses reordered to match reqs, regardless of the order the server
+// replied in.
+func (r *RPCRunner) send(
+	ctx context.Context, reqs []rpcRequest,
+) ([]rpcResponse, error) {
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, fmt.Errorf("encode rpc request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, r.endpoint, bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build rpc request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := r.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("rpc request to %s: %w", r.endpoint, err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"rpc request to %s: http %d", r.endpoint, httpResp.StatusCode,
+		)
+	}
+
+	var resps []rpcResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resps); err != nil {
+		return nil, fmt.Errorf("decode rpc response: %w", err)
+	}
+
+	if len(resps) != len(reqs) {
+		return nil, fmt.Errorf(
+			"rpc batch: expected %d responses, got %d", len(reqs), len(resps),
+		)
+	}
+
+	byID := make(map[int]rpcResponse, len(resps))
+	for _, resp := range resps {
+		byID[resp.ID] = resp
+	}
+
+	ordered := make([]rpcResponse, len(reqs))
+
+	for i, req := range reqs {
+		resp, ok := byID[req.ID]
+		if !ok {
+			return nil, fmt.Errorf(
+				"rpc batch: missing response for id %d", req.ID,
+			)
+		}
+
+		ordered[i] = resp
+	}
+
+	return ordered, nil
+}
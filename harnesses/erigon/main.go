@@ -1,6 +1,10 @@
 // Erigon harness reads a JSONL workload from stdin, applies state operations
 // using MDBX (Erigon's native key-value store), and outputs benchmark results
 // as JSON to stdout. State root is computed via go-ethereum's StackTrie.
+// compute_root is a checkpoint, not necessarily the end of the run: a
+// workload using begin_block/end_block to group per-block ops may call
+// compute_root many times, each producing its own result line on stdout,
+// before ending in a final compute_root with no further ops or a replay.
 package main
 
 import (
@@ -16,6 +20,8 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/erigontech/mdbx-go/mdbx"
@@ -47,7 +53,10 @@ type operation struct {
 }
 
 type result struct {
-	Client           string `json:"client"`
+	Client string `json:"client"`
+	// BlockIndex is the number of begin_block/end_block pairs seen so far.
+	// Always present, even for single-checkpoint workloads (where it is 0).
+	BlockIndex       int    `json:"block_index"`
 	StateRoot        string `json:"state_root"`
 	AccountsCreated  int    `json:"accounts_created"`
 	ContractsCreated int    `json:"contracts_created"`
@@ -56,6 +65,15 @@ type result struct {
 	TrieTimeMs       int64  `json:"trie_time_ms"`
 	DBWriteTimeMs    int64  `json:"db_write_time_ms"`
 	PeakMemoryBytes  uint64 `json:"peak_memory_bytes"`
+	// TrieParallelMs is the portion of TrieTimeMs spent in the worker-pool
+	// phase that computes per-account storage roots (see
+	// computeStorageRootsParallel); the remainder of TrieTimeMs is the
+	// sequential account-trie insertion phase.
+	TrieParallelMs int64 `json:"trie_parallel_ms"`
+	// TrieWorkers is the --trie-workers value passed to
+	// computeStorageRootsParallel, which runs at least one and at most one
+	// worker per account with storage.
+	TrieWorkers int `json:"trie_workers"`
 }
 
 // accountState tracks in-memory state for a single account.
@@ -74,6 +92,15 @@ type storageEntry struct {
 	value common.Hash
 }
 
+// deletedAccount records an address's incarnation at the moment
+// delete_account or selfdestruct_then_create superseded it, so writeMDBX
+// can prune that incarnation's now-orphaned PlainState storage range
+// instead of leaving it to accumulate as dead weight.
+type deletedAccount struct {
+	addr        common.Address
+	incarnation uint64
+}
+
 // codeEntry is a buffered code write.
 type codeEntry struct {
 	hash common.Hash
@@ -82,6 +109,9 @@ type codeEntry struct {
 
 func main() {
 	dbDir := flag.String("db", "", "database directory")
+	trieWorkers := flag.Int("trie-workers", runtime.GOMAXPROCS(0),
+		"number of workers for parallel per-account storage-root "+
+			"computation in computeStateRoot")
 	flag.Parse()
 
 	if *dbDir == "" {
@@ -103,11 +133,20 @@ func main() {
 	accounts := make(map[common.Address]*accountState)
 	var storageEntries []storageEntry
 	var codeEntries []codeEntry
+	var deletedAccounts []deletedAccount
+
+	// incarnations persists the next incarnation each address should use,
+	// surviving account deletion so a redeploy to the same address (see
+	// "delete_account"/"selfdestruct_then_create" below) doesn't reuse a
+	// storage key range an earlier incarnation already occupied.
+	incarnations := make(map[common.Address]uint64)
 
 	var (
 		numAccounts  int
 		numContracts int
 		numSlots     int
+		blockIndex   int
+		checkpoints  int
 	)
 
 	scanner := bufio.NewScanner(os.Stdin)
@@ -127,9 +166,10 @@ func main() {
 				bal = hexToUint256(op.Balance)
 			}
 			accounts[addr] = &accountState{
-				nonce:    op.Nonce,
-				balance:  bal,
-				codeHash: emptyCodeHash,
+				nonce:       op.Nonce,
+				balance:     bal,
+				codeHash:    emptyCodeHash,
+				incarnation: incarnations[addr],
 			}
 			numAccounts++
 
@@ -145,8 +185,24 @@ func main() {
 					op.Address,
 				)
 			}
+
+			if acc.codeHash != emptyCodeHash {
+				// Redeploying code to a live account without an
+				// intervening delete_account/selfdestruct_then_create
+				// still needs its old incarnation's storage pruned, same
+				// as an explicit delete. acc.incarnation alone can't
+				// signal this: it's inherited from the address's
+				// persistent incarnation counter on create_account, so an
+				// address with deletion history starts its next life
+				// already above zero before any code is deployed to it.
+				deletedAccounts = append(deletedAccounts, deletedAccount{
+					addr:        addr,
+					incarnation: acc.incarnation,
+				})
+			}
+
 			acc.codeHash = codeHash
-			acc.incarnation = 1
+			acc.incarnation++
 
 			codeEntries = append(codeEntries, codeEntry{
 				hash: codeHash,
@@ -154,6 +210,35 @@ func main() {
 			})
 			numContracts++
 
+		case "delete_account":
+			addr := common.HexToAddress(op.Address)
+
+			da, ok := applyDeleteAccount(addr, accounts, incarnations)
+			if !ok {
+				fatal(
+					"delete_account for unknown account %s",
+					op.Address,
+				)
+			}
+
+			deletedAccounts = append(deletedAccounts, da)
+
+		case "selfdestruct_then_create":
+			addr := common.HexToAddress(op.Address)
+			bal := uint256.NewInt(0)
+			if op.Balance != "" {
+				bal = hexToUint256(op.Balance)
+			}
+
+			da, pruned := applySelfdestructThenCreate(
+				addr, op.Nonce, bal, accounts, incarnations,
+			)
+			if pruned {
+				deletedAccounts = append(deletedAccounts, da)
+			}
+
+			numAccounts++
+
 		case "set_storage":
 			addr := common.HexToAddress(op.Address)
 			slot := common.HexToHash(op.Slot)
@@ -175,11 +260,29 @@ func main() {
 			})
 			numSlots++
 
+		case "begin_block":
+			blockIndex++
+
+		case "end_block":
+			// No-op marker; reserved so a future per-block validation pass
+			// has a clear boundary to hook into.
+
 		case "compute_root":
 			emitResult(
-				env, accounts, storageEntries, codeEntries,
-				start, numAccounts, numContracts, numSlots,
+				env, accounts, storageEntries, codeEntries, deletedAccounts,
+				start, blockIndex, numAccounts, numContracts, numSlots,
+				*trieWorkers,
 			)
+
+			checkpoints++
+
+			// compute_root is a checkpoint, not necessarily the end of the
+			// run: a workload using begin_block/end_block to group per-block
+			// ops may call compute_root many times, each producing its own
+			// result line on stdout, before ending in a final compute_root
+			// with no further ops or a replay.
+
+		case "replay":
 			return
 
 		default:
@@ -191,7 +294,9 @@ func main() {
 		fatal("read stdin: %v", err)
 	}
 
-	fatal("no compute_root operation found")
+	if checkpoints == 0 {
+		fatal("no compute_root operation found")
+	}
 }
 
 func emitResult(
@@ -199,17 +304,18 @@ func emitResult(
 	accounts map[common.Address]*accountState,
 	storage []storageEntry,
 	code []codeEntry,
+	deleted []deletedAccount,
 	start time.Time,
-	numAccounts, numContracts, numSlots int,
+	blockIndex, numAccounts, numContracts, numSlots, trieWorkers int,
 ) {
 	// Compute the state root via StackTrie.
 	trieStart := time.Now()
-	root := computeStateRoot(accounts, storage)
+	root, trieParallelMs := computeStateRoot(accounts, storage, trieWorkers)
 	trieMs := time.Since(trieStart).Milliseconds()
 
 	// Write all data to MDBX.
 	dbStart := time.Now()
-	if err := writeMDBX(env, accounts, storage, code); err != nil {
+	if err := writeMDBX(env, accounts, storage, code, deleted); err != nil {
 		fatal("write mdbx: %v", err)
 	}
 	dbWriteMs := time.Since(dbStart).Milliseconds()
@@ -219,6 +325,7 @@ func emitResult(
 
 	r := result{
 		Client:           "erigon",
+		BlockIndex:       blockIndex,
 		StateRoot:        root.Hex(),
 		AccountsCreated:  numAccounts,
 		ContractsCreated: numContracts,
@@ -227,6 +334,8 @@ func emitResult(
 		TrieTimeMs:       trieMs,
 		DBWriteTimeMs:    dbWriteMs,
 		PeakMemoryBytes:  m.Sys,
+		TrieParallelMs:   trieParallelMs,
+		TrieWorkers:      trieWorkers,
 	}
 
 	if err := json.NewEncoder(os.Stdout).Encode(r); err != nil {
@@ -235,20 +344,40 @@ func emitResult(
 }
 
 // computeStateRoot builds a standard Ethereum MPT state root from the
-// accumulated account/storage data using go-ethereum's StackTrie.
+// accumulated account/storage data using go-ethereum's StackTrie. Storage
+// roots are computed up front by computeStorageRootsParallel, fanned out
+// across trieWorkers; only the account-trie insertion, which StackTrie
+// requires in key order, is left sequential. Returns the state root and
+// the milliseconds spent in the parallel storage-root phase.
 func computeStateRoot(
 	accounts map[common.Address]*accountState,
 	storage []storageEntry,
-) common.Hash {
-	// Group storage by address for per-account storage root computation.
+	trieWorkers int,
+) (common.Hash, int64) {
+	// Group storage by address for per-account storage root computation,
+	// keeping only entries for the account's current incarnation. Entries
+	// from an incarnation a delete_account/selfdestruct_then_create has
+	// since superseded belong to a deleted account and must not
+	// contribute to its replacement's (or a deleted account's nonexistent)
+	// state root.
 	storageByAddr := make(
 		map[common.Address][]storageEntry, len(accounts),
 	)
 	for i := range storage {
 		addr := storage[i].addr
+
+		acc, ok := accounts[addr]
+		if !ok || acc.incarnation != storage[i].inc {
+			continue
+		}
+
 		storageByAddr[addr] = append(storageByAddr[addr], storage[i])
 	}
 
+	parallelStart := time.Now()
+	storageRoots := computeStorageRootsParallel(storageByAddr, trieWorkers)
+	parallelMs := time.Since(parallelStart).Milliseconds()
+
 	// Build sorted list of (addrHash, address) for deterministic
 	// StackTrie insertion order.
 	type addrWithHash struct {
@@ -274,8 +403,8 @@ func computeStateRoot(
 		acc := accounts[item.addr]
 
 		storageRoot := types.EmptyRootHash
-		if slots, ok := storageByAddr[item.addr]; ok && len(slots) > 0 {
-			storageRoot = computeStorageRoot(slots)
+		if root, ok := storageRoots[item.addr]; ok {
+			storageRoot = root
 		}
 
 		stateAcc := types.StateAccount{
@@ -292,7 +421,68 @@ func computeStateRoot(
 		accountTrie.Update(item.addrHash[:], data)
 	}
 
-	return accountTrie.Hash()
+	return accountTrie.Hash(), parallelMs
+}
+
+// computeStorageRootsParallel shards storageByAddr across trieWorkers
+// workers pulled from a shared work queue, each sorting its slot list and
+// building a per-account StackTrie to produce a storage root. This
+// mirrors the parallel reconstitution approach used in Erigon's
+// state-recon prototype, where account/storage work is fanned out to
+// independent workers and only the final MPT insertion is serialized to
+// preserve ordering.
+func computeStorageRootsParallel(
+	storageByAddr map[common.Address][]storageEntry, trieWorkers int,
+) map[common.Address]common.Hash {
+	roots := make(map[common.Address]common.Hash, len(storageByAddr))
+
+	if len(storageByAddr) == 0 {
+		return roots
+	}
+
+	addrs := make([]common.Address, 0, len(storageByAddr))
+	for addr := range storageByAddr {
+		addrs = append(addrs, addr)
+	}
+
+	if trieWorkers < 1 {
+		trieWorkers = 1
+	}
+	if trieWorkers > len(addrs) {
+		trieWorkers = len(addrs)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		next int64
+	)
+
+	wg.Add(trieWorkers)
+
+	for w := 0; w < trieWorkers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := atomic.AddInt64(&next, 1) - 1
+				if i >= int64(len(addrs)) {
+					return
+				}
+
+				addr := addrs[i]
+				root := computeStorageRoot(storageByAddr[addr])
+
+				mu.Lock()
+				roots[addr] = root
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return roots
 }
 
 // computeStorageRoot computes the storage trie root for a single account.
@@ -374,12 +564,15 @@ func createTables(env *mdbx.Env) error {
 	})
 }
 
-// writeMDBX writes all accounts, storage, and code to MDBX tables.
+// writeMDBX writes all accounts, storage, and code to MDBX tables, then
+// prunes the PlainState entries that deleted accounts left behind (see
+// pruneDeletedAccounts).
 func writeMDBX(
 	env *mdbx.Env,
 	accounts map[common.Address]*accountState,
 	storage []storageEntry,
 	code []codeEntry,
+	deleted []deletedAccount,
 ) error {
 	return env.Update(func(txn *mdbx.Txn) error {
 		plainDBI, err := txn.OpenDBI(tablePlainState, 0, nil, nil)
@@ -429,10 +622,147 @@ func writeMDBX(
 			}
 		}
 
+		if err := pruneDeletedAccounts(txn, plainDBI, accounts, deleted); err != nil {
+			return fmt.Errorf("prune deleted accounts: %w", err)
+		}
+
 		return nil
 	})
 }
 
+// pruneDeletedAccounts removes the PlainState entries a delete_account or
+// selfdestruct_then_create op superseded: the account's own record (unless
+// it was recreated and the write loop above already overwrote it) and its
+// old incarnation's storage range, found with a cursor scan over
+// [addr|oldIncarnation|0x00..00, addr|oldIncarnation|0xff..ff]. Without
+// this, redeployed contracts leave an orphaned storage range behind for
+// every incarnation they've ever had, which is exactly the storage-key
+// bloat Erigon's incarnation scheme is meant to let clients prune.
+func pruneDeletedAccounts(
+	txn *mdbx.Txn,
+	plainDBI mdbx.DBI,
+	accounts map[common.Address]*accountState,
+	deleted []deletedAccount,
+) error {
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	cur, err := txn.OpenCursor(plainDBI)
+	if err != nil {
+		return fmt.Errorf("open cursor: %w", err)
+	}
+	defer cur.Close()
+
+	for _, d := range deleted {
+		if _, ok := accounts[d.addr]; !ok {
+			if err := txn.Del(plainDBI, d.addr[:], nil); err != nil && !mdbx.IsNotFound(err) {
+				return fmt.Errorf(
+					"delete account record %s: %w", d.addr.Hex(), err,
+				)
+			}
+		}
+
+		rangeStart := makeStorageKey(d.addr, d.incarnation, common.Hash{})
+		key, _, err := cur.Get(rangeStart, nil, mdbx.SetRange)
+
+		for !mdbx.IsNotFound(err) {
+			if err != nil {
+				return fmt.Errorf(
+					"scan storage range for %s inc %d: %w",
+					d.addr.Hex(), d.incarnation, err,
+				)
+			}
+
+			if !storageKeyInRange(key, d.addr, d.incarnation) {
+				break
+			}
+
+			if err := cur.Del(0); err != nil {
+				return fmt.Errorf(
+					"delete storage %x for %s inc %d: %w",
+					key, d.addr.Hex(), d.incarnation, err,
+				)
+			}
+
+			key, _, err = cur.Get(nil, nil, mdbx.Next)
+		}
+	}
+
+	return nil
+}
+
+// applyDeleteAccount removes addr's live account from accounts, bumping its
+// next incarnation so a later create_account or selfdestruct_then_create to
+// the same address doesn't reuse a storage key range this incarnation
+// occupied. Returns the deletedAccount entry to prune and false if addr had
+// no live account.
+func applyDeleteAccount(
+	addr common.Address,
+	accounts map[common.Address]*accountState,
+	incarnations map[common.Address]uint64,
+) (deletedAccount, bool) {
+	acc, ok := accounts[addr]
+	if !ok {
+		return deletedAccount{}, false
+	}
+
+	da := deletedAccount{addr: addr, incarnation: acc.incarnation}
+	incarnations[addr] = acc.incarnation + 1
+	delete(accounts, addr)
+
+	return da, true
+}
+
+// applySelfdestructThenCreate redeploys addr to a fresh accountState with
+// the given nonce/balance and empty code, bumping its incarnation first if a
+// live account existed (selfdestruct_then_create on a never-deployed address
+// is just a create, with no incarnation to prune). Returns the deletedAccount
+// entry to prune and false if there was nothing to prune.
+func applySelfdestructThenCreate(
+	addr common.Address,
+	nonce uint64,
+	balance *uint256.Int,
+	accounts map[common.Address]*accountState,
+	incarnations map[common.Address]uint64,
+) (deletedAccount, bool) {
+	var (
+		da     deletedAccount
+		pruned bool
+	)
+
+	if acc, ok := accounts[addr]; ok {
+		da = deletedAccount{addr: addr, incarnation: acc.incarnation}
+		incarnations[addr] = acc.incarnation + 1
+		pruned = true
+	}
+
+	accounts[addr] = &accountState{
+		nonce:       nonce,
+		balance:     balance,
+		codeHash:    emptyCodeHash,
+		incarnation: incarnations[addr],
+	}
+
+	return da, pruned
+}
+
+// storageKeyInRange reports whether a PlainState key (see makeStorageKey)
+// belongs to addr's incarnation.
+func storageKeyInRange(
+	key []byte, addr common.Address, incarnation uint64,
+) bool {
+	if len(key) != 20+8+32 {
+		return false
+	}
+
+	if !bytes.Equal(key[:20], addr[:]) {
+		return false
+	}
+
+	return binary.BigEndian.Uint64(key[20:28]) == incarnation
+}
+
 // makeStorageKey builds the PlainState storage key:
 // address(20) + incarnation(8, big-endian) + slot(32).
 func makeStorageKey(
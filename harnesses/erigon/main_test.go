@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+func TestApplyDeleteAccountBumpsIncarnation(t *testing.T) {
+	addr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	accounts := map[common.Address]*accountState{
+		addr: {nonce: 1, balance: uint256.NewInt(0), codeHash: emptyCodeHash, incarnation: 0},
+	}
+	incarnations := make(map[common.Address]uint64)
+
+	da, ok := applyDeleteAccount(addr, accounts, incarnations)
+	if !ok {
+		t.Fatal("applyDeleteAccount reported no live account, want ok")
+	}
+	if da.addr != addr || da.incarnation != 0 {
+		t.Errorf("deletedAccount = %+v, want {addr: %s, incarnation: 0}", da, addr)
+	}
+	if _, live := accounts[addr]; live {
+		t.Error("account still present in accounts after delete_account")
+	}
+	if got := incarnations[addr]; got != 1 {
+		t.Errorf("incarnations[addr] = %d, want 1", got)
+	}
+}
+
+func TestApplyDeleteAccountUnknown(t *testing.T) {
+	addr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	accounts := map[common.Address]*accountState{}
+	incarnations := make(map[common.Address]uint64)
+
+	if _, ok := applyDeleteAccount(addr, accounts, incarnations); ok {
+		t.Error("applyDeleteAccount reported ok for an address with no live account")
+	}
+}
+
+// TestApplySelfdestructThenCreateFreshIncarnation exercises delete-then-
+// recreate: a live account is superseded by selfdestruct_then_create, which
+// must bump the address's incarnation so the new account's storage key range
+// (see makeStorageKey) doesn't overlap the superseded incarnation's, and must
+// report that incarnation back so the caller can prune its storage.
+func TestApplySelfdestructThenCreateFreshIncarnation(t *testing.T) {
+	addr := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	accounts := map[common.Address]*accountState{
+		addr: {nonce: 1, balance: uint256.NewInt(5), codeHash: common.HexToHash("0xaa"), incarnation: 0},
+	}
+	incarnations := make(map[common.Address]uint64)
+
+	da, pruned := applySelfdestructThenCreate(addr, 7, uint256.NewInt(9), accounts, incarnations)
+	if !pruned {
+		t.Fatal("applySelfdestructThenCreate reported nothing to prune, want the old incarnation")
+	}
+	if da.addr != addr || da.incarnation != 0 {
+		t.Errorf("deletedAccount = %+v, want {addr: %s, incarnation: 0}", da, addr)
+	}
+
+	acc, ok := accounts[addr]
+	if !ok {
+		t.Fatal("account missing after selfdestruct_then_create")
+	}
+	if acc.incarnation != 1 {
+		t.Errorf("new account incarnation = %d, want 1", acc.incarnation)
+	}
+	if acc.codeHash != emptyCodeHash {
+		t.Errorf("new account codeHash = %s, want emptyCodeHash", acc.codeHash)
+	}
+	if acc.nonce != 7 || acc.balance.Uint64() != 9 {
+		t.Errorf("new account = {nonce: %d, balance: %s}, want {nonce: 7, balance: 9}", acc.nonce, acc.balance)
+	}
+
+	// The superseded incarnation's storage range must no longer be the one
+	// a write to the new account would touch.
+	oldKey := makeStorageKey(addr, da.incarnation, common.HexToHash("0x01"))
+	if storageKeyInRange(oldKey, addr, acc.incarnation) {
+		t.Error("old incarnation's storage key reported in range of the new incarnation")
+	}
+	if !storageKeyInRange(oldKey, addr, da.incarnation) {
+		t.Error("old incarnation's storage key not in range of its own incarnation")
+	}
+}
+
+func TestApplySelfdestructThenCreateNoPriorAccount(t *testing.T) {
+	addr := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	accounts := map[common.Address]*accountState{}
+	incarnations := make(map[common.Address]uint64)
+
+	_, pruned := applySelfdestructThenCreate(addr, 0, uint256.NewInt(0), accounts, incarnations)
+	if pruned {
+		t.Error("applySelfdestructThenCreate reported something to prune for a never-deployed address")
+	}
+	if got := accounts[addr].incarnation; got != 0 {
+		t.Errorf("new account incarnation = %d, want 0", got)
+	}
+}
+
+// TestComputeStorageRootsParallelMatchesSingleWorker checks that sharding
+// accounts across multiple workers doesn't change the per-account storage
+// root any single worker would compute alone.
+func TestComputeStorageRootsParallelMatchesSingleWorker(t *testing.T) {
+	storageByAddr := map[common.Address][]storageEntry{
+		common.HexToAddress("0x01"): {
+			{slot: common.HexToHash("0x01"), value: common.HexToHash("0xaa")},
+			{slot: common.HexToHash("0x02"), value: common.HexToHash("0xbb")},
+		},
+		common.HexToAddress("0x02"): {
+			{slot: common.HexToHash("0x03"), value: common.HexToHash("0xcc")},
+		},
+		common.HexToAddress("0x03"): {
+			{slot: common.HexToHash("0x04"), value: common.HexToHash("0xdd")},
+			{slot: common.HexToHash("0x05"), value: common.HexToHash("0xee")},
+			{slot: common.HexToHash("0x06"), value: common.HexToHash("0xff")},
+		},
+	}
+
+	single := computeStorageRootsParallel(storageByAddr, 1)
+	parallel := computeStorageRootsParallel(storageByAddr, 4)
+
+	if len(single) != len(parallel) {
+		t.Fatalf("len(single) = %d, len(parallel) = %d", len(single), len(parallel))
+	}
+
+	for addr, root := range single {
+		if parallel[addr] != root {
+			t.Errorf("root for %s: trieWorkers=1 gave %s, trieWorkers=4 gave %s", addr, root, parallel[addr])
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/weiihann/statoor/harness"
+)
+
+// GenerateComparison writes a markdown state-root agreement table, plus a
+// bisection summary when the clients diverged, for a harness.ComparisonReport.
+func GenerateComparison(w io.Writer, r *harness.ComparisonReport) error {
+	if r == nil || len(r.Roots) == 0 {
+		return fmt.Errorf("no comparison results to report")
+	}
+
+	clients := make([]string, 0, len(r.Roots))
+	for client := range r.Roots {
+		clients = append(clients, client)
+	}
+
+	sort.Strings(clients)
+
+	fmt.Fprintln(w, "## State Root Comparison")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Majority root: `%s`\n", r.MajorityRoot)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Client | State Root | Agrees |")
+	fmt.Fprintln(w, "|--------|------------|--------|")
+
+	for _, client := range clients {
+		agrees := "yes"
+		if !r.Agreement[client] {
+			agrees = "NO"
+		}
+
+		fmt.Fprintf(w, "| %s | %s | %s |\n", client, r.Roots[client], agrees)
+	}
+
+	if r.Bisection != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "## Divergence Bisection")
+		fmt.Fprintln(w)
+		fmt.Fprintf(w,
+			"%s and %s first disagree after op %d of %d.\n",
+			r.Bisection.Majority, r.Bisection.Outlier,
+			r.Bisection.OpIndex, r.Bisection.TotalOps,
+		)
+	}
+
+	return nil
+}
+
+// GenerateComparisonJSON writes a harness.ComparisonReport as JSON to w,
+// suitable for machine consumption by CI.
+func GenerateComparisonJSON(w io.Writer, r *harness.ComparisonReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ConformanceResult records whether a single client's run of a single
+// pinned test vector produced the vector's expected_state_root.
+type ConformanceResult struct {
+	Vector   string `json:"vector"`
+	Client   string `json:"client"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual,omitempty"`
+	Pass     bool   `json:"pass"`
+	// Error holds the harness run failure, if any. Actual and Pass are
+	// meaningless when Error is set.
+	Error string `json:"error,omitempty"`
+}
+
+// GenerateConformance writes a markdown pass/fail/error table, one row per
+// vector/client pair, suitable for CI gating.
+func GenerateConformance(w io.Writer, results []ConformanceResult) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no conformance results to report")
+	}
+
+	fmt.Fprintln(w, "## Conformance Results")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Vector | Client | Expected | Actual | Status |")
+	fmt.Fprintln(w, "|--------|--------|----------|--------|--------|")
+
+	for _, r := range results {
+		status := "PASS"
+		actual := r.Actual
+
+		switch {
+		case r.Error != "":
+			status = "ERROR"
+			actual = r.Error
+		case !r.Pass:
+			status = "FAIL"
+		}
+
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+			r.Vector, r.Client, r.Expected, actual, status,
+		)
+	}
+
+	return nil
+}
+
+// GenerateConformanceJSON writes conformance results as JSON to w, suitable
+// for machine consumption by CI.
+func GenerateConformanceJSON(w io.Writer, results []ConformanceResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(results)
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGenerateConformancePassFail(t *testing.T) {
+	results := []ConformanceResult{
+		{Vector: "vec1", Client: "geth", Expected: "0xabc", Actual: "0xabc", Pass: true},
+		{Vector: "vec1", Client: "reth", Expected: "0xabc", Actual: "0xdef", Pass: false},
+		{Vector: "vec1", Client: "erigon", Expected: "0xabc", Error: "harness erigon failed: exit status 1"},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateConformance(&buf, results); err != nil {
+		t.Fatalf("GenerateConformance failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "PASS") {
+		t.Error("expected PASS status")
+	}
+	if !strings.Contains(output, "FAIL") {
+		t.Error("expected FAIL status")
+	}
+	if !strings.Contains(output, "ERROR") {
+		t.Error("expected ERROR status")
+	}
+}
+
+func TestGenerateConformanceEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateConformance(&buf, nil); err == nil {
+		t.Error("expected error for empty results")
+	}
+}
+
+func TestGenerateConformanceJSON(t *testing.T) {
+	results := []ConformanceResult{
+		{Vector: "vec1", Client: "geth", Expected: "0xabc", Actual: "0xabc", Pass: true},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateConformanceJSON(&buf, results); err != nil {
+		t.Fatalf("GenerateConformanceJSON failed: %v", err)
+	}
+
+	var parsed []ConformanceResult
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if len(parsed) != 1 || parsed[0].Vector != "vec1" {
+		t.Errorf("unexpected parsed result: %+v", parsed)
+	}
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// OpEvent is a single per-operation latency sample emitted by a harness
+// running in replay mode.
+type OpEvent struct {
+	Type      string `json:"type"`
+	Index     int    `json:"index"`
+	Op        string `json:"op"`
+	ElapsedNs int64  `json:"elapsed_ns"`
+}
+
+// ParseReplayStream decodes a replay-mode NDJSON stream, returning the
+// per-operation latency events. Memory samples and the terminal result
+// line are skipped.
+func ParseReplayStream(r io.Reader) ([]OpEvent, error) {
+	var events []OpEvent
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1<<20), 1<<20)
+
+	for scanner.Scan() {
+		var tagged struct {
+			Type string `json:"type"`
+		}
+
+		line := scanner.Bytes()
+		if err := json.Unmarshal(line, &tagged); err != nil {
+			return nil, fmt.Errorf("decode replay line: %w", err)
+		}
+
+		if tagged.Type != "op" {
+			continue
+		}
+
+		var event OpEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("decode op event: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan replay stream: %w", err)
+	}
+
+	return events, nil
+}
+
+// GenerateProfile writes a markdown latency summary (p50/p95/p99 per op
+// type, per client) for the given replay streams, keyed by client name.
+func GenerateProfile(w io.Writer, streams map[string][]OpEvent) error {
+	if len(streams) == 0 {
+		return fmt.Errorf("no replay streams to report")
+	}
+
+	clients := make([]string, 0, len(streams))
+	for client := range streams {
+		clients = append(clients, client)
+	}
+
+	sort.Strings(clients)
+
+	fmt.Fprintln(w, "## Latency Profile")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Client | Op | Count | p50 | p95 | p99 |")
+	fmt.Fprintln(w, "|--------|----|----|-----|-----|-----|")
+
+	for _, client := range clients {
+		byOp := groupByOp(streams[client])
+
+		ops := make([]string, 0, len(byOp))
+		for op := range byOp {
+			ops = append(ops, op)
+		}
+
+		sort.Strings(ops)
+
+		for _, op := range ops {
+			durations := byOp[op]
+			sort.Slice(durations, func(i, j int) bool {
+				return durations[i] < durations[j]
+			})
+
+			fmt.Fprintf(w, "| %s | %s | %d | %s | %s | %s |\n",
+				client, op, len(durations),
+				formatNs(percentile(durations, 50)),
+				formatNs(percentile(durations, 95)),
+				formatNs(percentile(durations, 99)),
+			)
+		}
+	}
+
+	return nil
+}
+
+func groupByOp(events []OpEvent) map[string][]int64 {
+	byOp := make(map[string][]int64)
+
+	for _, e := range events {
+		byOp[e.Op] = append(byOp[e.Op], e.ElapsedNs)
+	}
+
+	return byOp
+}
+
+// percentile returns the p-th percentile of a sorted (ascending) slice.
+func percentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := (p * (len(sorted) - 1)) / 100
+
+	return sorted[idx]
+}
+
+func formatNs(ns int64) string {
+	switch {
+	case ns >= 1_000_000:
+		return fmt.Sprintf("%.2fms", float64(ns)/1_000_000)
+	case ns >= 1_000:
+		return fmt.Sprintf("%.2fus", float64(ns)/1_000)
+	default:
+		return fmt.Sprintf("%dns", ns)
+	}
+}
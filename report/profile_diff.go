@@ -0,0 +1,100 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/weiihann/statoor/harness"
+)
+
+// anyProfiles reports whether any result carries profiling artifacts from a
+// --profile-dir run.
+func anyProfiles(results []harness.Result) bool {
+	for _, r := range results {
+		if r.CPUProfilePath != "" || r.HeapProfilePath != "" || r.TracePath != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderProfileLinks writes a table of per-client profiling artifact paths.
+func renderProfileLinks(w io.Writer, results []harness.Result) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Client | CPU Profile | Heap Profile | Trace |")
+	fmt.Fprintln(w, "|--------|-------------|--------------|-------|")
+
+	for _, r := range results {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+			r.Client,
+			orDash(r.CPUProfilePath),
+			orDash(r.HeapProfilePath),
+			orDash(r.TracePath),
+		)
+	}
+}
+
+// renderProfileDiff runs `go tool pprof -top -diff_base` between the first
+// result's CPU profile and every other result's, surfacing where each
+// client spends more or less time relative to the baseline. Only runs when
+// at least two results carry a CPU profile; pprof invocation failures (e.g.
+// no Go toolchain available where the report is rendered) are reported
+// inline rather than failing the whole report.
+func renderProfileDiff(w io.Writer, results []harness.Result) {
+	var withProfile []harness.Result
+
+	for _, r := range results {
+		if r.CPUProfilePath != "" {
+			withProfile = append(withProfile, r)
+		}
+	}
+
+	if len(withProfile) < 2 {
+		return
+	}
+
+	base := withProfile[0]
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "CPU profile diff (baseline: %s)\n\n", base.Client)
+
+	for _, r := range withProfile[1:] {
+		top, err := pprofDiffTop(base.CPUProfilePath, r.CPUProfilePath)
+
+		fmt.Fprintf(w, "`%s` vs `%s`:\n\n", r.Client, base.Client)
+		fmt.Fprintln(w, "```")
+
+		if err != nil {
+			fmt.Fprintf(w, "pprof diff unavailable: %v\n", err)
+		} else {
+			fmt.Fprintln(w, top)
+		}
+
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w)
+	}
+}
+
+// pprofDiffTop shells out to `go tool pprof -top -diff_base=base target` and
+// returns its top-functions output.
+func pprofDiffTop(base, target string) (string, error) {
+	out, err := exec.Command(
+		"go", "tool", "pprof", "-top", "-diff_base="+base, target,
+	).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("pprof diff: %w", err)
+	}
+
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseReplayStream(t *testing.T) {
+	input := `{"type":"op","index":0,"op":"create_account","elapsed_ns":100}
+{"type":"mem","index":0,"heap_alloc":1024,"sys":2048}
+{"type":"op","index":1,"op":"set_storage","elapsed_ns":200}
+{"client":"geth","state_root":"0xabc"}
+`
+
+	events, err := ParseReplayStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseReplayStream failed: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+
+	if events[0].Op != "create_account" || events[0].ElapsedNs != 100 {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+
+	if events[1].Op != "set_storage" || events[1].ElapsedNs != 200 {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestGenerateProfile(t *testing.T) {
+	streams := map[string][]OpEvent{
+		"geth": {
+			{Op: "set_storage", ElapsedNs: 100},
+			{Op: "set_storage", ElapsedNs: 200},
+			{Op: "set_storage", ElapsedNs: 300},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateProfile(&buf, streams); err != nil {
+		t.Fatalf("GenerateProfile failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "geth") {
+		t.Error("expected geth in output")
+	}
+	if !strings.Contains(output, "set_storage") {
+		t.Error("expected set_storage in output")
+	}
+}
+
+func TestGenerateProfileEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateProfile(&buf, nil); err == nil {
+		t.Error("expected error for empty streams")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []int64{10, 20, 30, 40, 50}
+
+	if got := percentile(sorted, 50); got != 30 {
+		t.Errorf("p50 = %d, want 30", got)
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile of empty slice = %d, want 0", got)
+	}
+}
@@ -11,19 +11,43 @@ import (
 	"github.com/weiihann/statoor/harness"
 )
 
-// Generate writes a markdown comparison table for the given results.
+// Generate writes a markdown comparison table for the given results. When
+// results span more than one trie scheme, each scheme is grouped under its
+// own subsection so hash- and path-scheme runs aren't compared directly.
 func Generate(w io.Writer, results []harness.Result) error {
 	if len(results) == 0 {
 		return fmt.Errorf("no results to report")
 	}
 
-	rootMatch := checkStateRoots(results)
-	fastestMs := findFastest(results)
-
-	// Header.
 	fmt.Fprintln(w, "## Benchmark Results")
 	fmt.Fprintln(w)
 
+	groups, schemes := groupByScheme(results)
+
+	for _, scheme := range schemes {
+		if len(schemes) > 1 {
+			label := scheme
+			if label == "" {
+				label = "unspecified"
+			}
+
+			fmt.Fprintf(w, "### Scheme: %s\n\n", label)
+		}
+
+		if err := generateGroup(w, groups[scheme]); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+func generateGroup(w io.Writer, results []harness.Result) error {
+	rootMatch := checkStateRoots(results)
+	fastestMs := findFastest(results)
+
 	// State root check.
 	if rootMatch {
 		fmt.Fprintln(w, "State roots: **all match**")
@@ -37,11 +61,29 @@ func Generate(w io.Writer, results []harness.Result) error {
 
 	fmt.Fprintln(w)
 
+	hasWarmup := anyWarmup(results)
+	hasContention := anyParallel(results)
+
 	// Table header.
-	fmt.Fprintln(w, "| Client | Elapsed | Trie Time | DB Write "+
-		"| Peak Mem | DB Size | Speedup |")
-	fmt.Fprintln(w, "|--------|---------|-----------|----------"+
-		"|----------|---------|---------|")
+	header := "| Client | Elapsed | Trie Time | DB Write " +
+		"| Peak Mem | DB Size | Speedup |"
+	divider := "|--------|---------|-----------|----------" +
+		"|----------|---------|---------|"
+
+	if hasWarmup {
+		header = "| Client | Warmup (cold) | Elapsed (warm) | Trie Time " +
+			"| DB Write | Peak Mem | DB Size | Speedup |"
+		divider = "|--------|----------------|-----------------|-----------" +
+			"|----------|----------|---------|---------|"
+	}
+
+	if hasContention {
+		header += " Contention |"
+		divider += "------------|"
+	}
+
+	fmt.Fprintln(w, header)
+	fmt.Fprintln(w, divider)
 
 	for _, r := range results {
 		speedup := 1.0
@@ -49,15 +91,36 @@ func Generate(w io.Writer, results []harness.Result) error {
 			speedup = float64(r.ElapsedMs) / float64(fastestMs)
 		}
 
-		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %.2fx |\n",
-			r.Client,
-			formatMs(r.ElapsedMs),
-			formatMs(r.TrieTimeMs),
-			formatMs(r.DBWriteTimeMs),
-			formatBytes(r.PeakMemoryBytes),
-			formatBytes(r.DBSizeBytes),
-			speedup,
-		)
+		row := ""
+
+		if hasWarmup {
+			row = fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %.2fx |",
+				r.Client,
+				formatMs(r.WarmupElapsedMs),
+				formatMs(r.ElapsedMs),
+				formatMs(r.TrieTimeMs),
+				formatMs(r.DBWriteTimeMs),
+				formatBytes(r.PeakMemoryBytes),
+				formatBytes(r.DBSizeBytes),
+				speedup,
+			)
+		} else {
+			row = fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %.2fx |",
+				r.Client,
+				formatMs(r.ElapsedMs),
+				formatMs(r.TrieTimeMs),
+				formatMs(r.DBWriteTimeMs),
+				formatBytes(r.PeakMemoryBytes),
+				formatBytes(r.DBSizeBytes),
+				speedup,
+			)
+		}
+
+		if hasContention {
+			row += fmt.Sprintf(" %s |", contentionLabel(r))
+		}
+
+		fmt.Fprintln(w, row)
 	}
 
 	fmt.Fprintln(w)
@@ -75,6 +138,31 @@ func Generate(w io.Writer, results []harness.Result) error {
 		)
 	}
 
+	if anyDBConfig(results) {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Client | Backend | Cache | Handles "+
+			"| Write Buffer | Max Open Files | Compression |")
+		fmt.Fprintln(w, "|--------|---------|-------|----------"+
+			"|--------------|----------------|-------------|")
+
+		for _, r := range results {
+			fmt.Fprintf(w, "| %s | %s | %d MB | %d | %d MB | %d | %s |\n",
+				r.Client,
+				r.DBConfig.Backend,
+				r.DBConfig.CacheMB,
+				r.DBConfig.Handles,
+				r.DBConfig.WriteBufferMB,
+				r.DBConfig.MaxOpenFiles,
+				r.DBConfig.Compression,
+			)
+		}
+	}
+
+	if anyProfiles(results) {
+		renderProfileLinks(w, results)
+		renderProfileDiff(w, results)
+	}
+
 	return nil
 }
 
@@ -86,6 +174,70 @@ func GenerateJSON(w io.Writer, results []harness.Result) error {
 	return enc.Encode(results)
 }
 
+// groupByScheme partitions results by their Scheme field, preserving the
+// order each scheme was first seen in.
+func groupByScheme(
+	results []harness.Result,
+) (map[string][]harness.Result, []string) {
+	groups := make(map[string][]harness.Result)
+
+	var order []string
+
+	for _, r := range results {
+		if _, ok := groups[r.Scheme]; !ok {
+			order = append(order, r.Scheme)
+		}
+
+		groups[r.Scheme] = append(groups[r.Scheme], r)
+	}
+
+	return groups, order
+}
+
+func anyDBConfig(results []harness.Result) bool {
+	for _, r := range results {
+		if r.DBConfig.Backend != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func anyWarmup(results []harness.Result) bool {
+	for _, r := range results {
+		if r.WarmupElapsedMs > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func anyParallel(results []harness.Result) bool {
+	for _, r := range results {
+		if r.Parallel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contentionLabel flags a run as contended when it executed under
+// --parallelism alongside at least one other run. harness.Result doesn't
+// track NUMA topology, so this can't distinguish clients that landed on
+// disjoint NUMA nodes from ones that shared a node; it's a conservative
+// warning that CPU-time and peak-memory figures may be skewed by
+// contention, not a precise diagnosis.
+func contentionLabel(r harness.Result) string {
+	if r.Parallel {
+		return "warning"
+	}
+
+	return "-"
+}
+
 func checkStateRoots(results []harness.Result) bool {
 	if len(results) < 2 {
 		return true
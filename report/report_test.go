@@ -82,6 +82,153 @@ func TestGenerateMismatchedRoots(t *testing.T) {
 	}
 }
 
+func TestGenerateWarmCold(t *testing.T) {
+	results := []harness.Result{
+		{
+			Client:          "geth",
+			StateRoot:       "0xabc",
+			ElapsedMs:       100,
+			WarmupElapsedMs: 5000,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(&buf, results); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Warmup (cold)") {
+		t.Error("expected warmup column header")
+	}
+	if !strings.Contains(output, "5.00s") {
+		t.Error("expected formatted warmup duration")
+	}
+}
+
+func TestGenerateGroupsByScheme(t *testing.T) {
+	results := []harness.Result{
+		{Client: "geth", StateRoot: "0xabc", ElapsedMs: 100, Scheme: "hash"},
+		{Client: "geth", StateRoot: "0xabc", ElapsedMs: 200, Scheme: "path"},
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(&buf, results); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "### Scheme: hash") {
+		t.Error("expected hash scheme heading")
+	}
+	if !strings.Contains(output, "### Scheme: path") {
+		t.Error("expected path scheme heading")
+	}
+}
+
+func TestGenerateDBConfig(t *testing.T) {
+	results := []harness.Result{
+		{
+			Client:    "geth",
+			StateRoot: "0xabc",
+			ElapsedMs: 100,
+			DBConfig: harness.DBConfig{
+				Backend:       "pebble",
+				CacheMB:       1024,
+				Handles:       512,
+				WriteBufferMB: 128,
+				MaxOpenFiles:  512,
+				Compression:   "snappy",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(&buf, results); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Backend") {
+		t.Error("expected DB config column header")
+	}
+	if !strings.Contains(output, "pebble") {
+		t.Error("expected pebble backend in output")
+	}
+	if !strings.Contains(output, "1024 MB") {
+		t.Error("expected formatted cache size")
+	}
+}
+
+func TestGenerateContentionWarning(t *testing.T) {
+	results := []harness.Result{
+		{Client: "geth", StateRoot: "0xabc", ElapsedMs: 100, Parallel: true},
+		{Client: "reth", StateRoot: "0xabc", ElapsedMs: 200, Parallel: true},
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(&buf, results); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Contention") {
+		t.Error("expected contention column header")
+	}
+	if !strings.Contains(output, "warning") {
+		t.Error("expected warning label for parallel runs")
+	}
+}
+
+func TestGenerateNoContentionColumnWhenSequential(t *testing.T) {
+	results := []harness.Result{
+		{Client: "geth", StateRoot: "0xabc", ElapsedMs: 100},
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(&buf, results); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "Contention") {
+		t.Error("expected no contention column for sequential runs")
+	}
+}
+
+func TestGenerateProfileLinks(t *testing.T) {
+	results := []harness.Result{
+		{
+			Client:          "geth",
+			StateRoot:       "0xabc",
+			ElapsedMs:       100,
+			CPUProfilePath:  "/tmp/profiles/geth/cpu.pprof",
+			HeapProfilePath: "/tmp/profiles/geth/heap.pprof",
+			TracePath:       "/tmp/profiles/geth/trace.out",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(&buf, results); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "CPU Profile") {
+		t.Error("expected CPU profile column header")
+	}
+	if !strings.Contains(output, "/tmp/profiles/geth/cpu.pprof") {
+		t.Error("expected cpu profile path in output")
+	}
+	if !strings.Contains(output, "/tmp/profiles/geth/trace.out") {
+		t.Error("expected trace path in output")
+	}
+}
+
 func TestGenerateEmpty(t *testing.T) {
 	var buf bytes.Buffer
 	err := Generate(&buf, nil)
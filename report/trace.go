@@ -0,0 +1,209 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DBTraceEvent is a single structured database-phase trace event emitted
+// by a harness run with --trace-path.
+type DBTraceEvent struct {
+	OpIndex int    `json:"op_index"`
+	Phase   string `json:"phase"`
+	Ns      int64  `json:"ns"`
+	Allocs  uint64 `json:"allocs"`
+}
+
+// ParseTraceStream decodes a --trace-path NDJSON stream into its events.
+// Unlike the replay stream, every line is a trace event, so there is no
+// "type" discriminator to filter on.
+func ParseTraceStream(r io.Reader) ([]DBTraceEvent, error) {
+	var events []DBTraceEvent
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1<<20), 1<<20)
+
+	for scanner.Scan() {
+		var event DBTraceEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("decode db trace event: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan trace stream: %w", err)
+	}
+
+	return events, nil
+}
+
+// traceDocument is a Chrome trace-viewer (chrome://tracing, also loadable
+// by Perfetto) JSON document: a flat list of complete ("X") events.
+type traceDocument struct {
+	TraceEvents []traceEvent `json:"traceEvents"`
+}
+
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur"`
+	PID  int            `json:"pid"`
+	TID  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// GenerateTrace writes a Chrome trace-viewer JSON document for the given
+// per-client DB trace streams, keyed by client name. Each client is laid
+// out on its own pid/track; within a client, events have no recorded
+// start timestamp (only a phase and a duration), so they are placed
+// back-to-back in event order to produce a readable, non-overlapping
+// timeline rather than a wall-clock-accurate one.
+func GenerateTrace(w io.Writer, streams map[string][]DBTraceEvent) error {
+	if len(streams) == 0 {
+		return fmt.Errorf("no trace streams to report")
+	}
+
+	clients := make([]string, 0, len(streams))
+	for client := range streams {
+		clients = append(clients, client)
+	}
+
+	sort.Strings(clients)
+
+	var doc traceDocument
+
+	for pid, client := range clients {
+		var tsUs int64
+
+		for _, e := range streams[client] {
+			durUs := e.Ns / 1000
+
+			doc.TraceEvents = append(doc.TraceEvents, traceEvent{
+				Name: e.Phase,
+				Cat:  "db",
+				Ph:   "X",
+				Ts:   tsUs,
+				Dur:  durUs,
+				PID:  pid,
+				TID:  0,
+				Args: map[string]any{
+					"client":   client,
+					"op_index": e.OpIndex,
+					"allocs":   e.Allocs,
+				},
+			})
+
+			tsUs += durUs
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode trace document: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateTraceSummary writes a markdown latency and allocation-churn
+// summary (p50/p95/p99 per phase, per client, plus allocations per 10k
+// ops) for the given DB trace streams.
+func GenerateTraceSummary(w io.Writer, streams map[string][]DBTraceEvent) error {
+	if len(streams) == 0 {
+		return fmt.Errorf("no trace streams to report")
+	}
+
+	clients := make([]string, 0, len(streams))
+	for client := range streams {
+		clients = append(clients, client)
+	}
+
+	sort.Strings(clients)
+
+	fmt.Fprintln(w, "## DB Trace Summary")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Client | Phase | Count | p50 | p95 | p99 | Allocs/10k ops |")
+	fmt.Fprintln(w, "|--------|-------|-------|-----|-----|-----|----------------|")
+
+	for _, client := range clients {
+		events := streams[client]
+
+		// Total op count is derived from the whole stream, not a single
+		// phase's events, since phases like "hash"/"commit" only fire once
+		// per compute_root and would otherwise massively understate it.
+		totalOps := maxOpIndex(events) + 1
+
+		byPhase := groupByPhase(events)
+
+		phases := make([]string, 0, len(byPhase))
+		for phase := range byPhase {
+			phases = append(phases, phase)
+		}
+
+		sort.Strings(phases)
+
+		for _, phase := range phases {
+			phaseEvents := byPhase[phase]
+
+			durations := make([]int64, len(phaseEvents))
+			for i, e := range phaseEvents {
+				durations[i] = e.Ns
+			}
+
+			sort.Slice(durations, func(i, j int) bool {
+				return durations[i] < durations[j]
+			})
+
+			fmt.Fprintf(w, "| %s | %s | %d | %s | %s | %s | %s |\n",
+				client, phase, len(durations),
+				formatNs(percentile(durations, 50)),
+				formatNs(percentile(durations, 95)),
+				formatNs(percentile(durations, 99)),
+				formatAllocsPer10k(phaseEvents, totalOps),
+			)
+		}
+	}
+
+	return nil
+}
+
+func groupByPhase(events []DBTraceEvent) map[string][]DBTraceEvent {
+	byPhase := make(map[string][]DBTraceEvent)
+
+	for _, e := range events {
+		byPhase[e.Phase] = append(byPhase[e.Phase], e)
+	}
+
+	return byPhase
+}
+
+// formatAllocsPer10k scales total allocations across events to a rate per
+// 10,000 operations, given totalOps operations in the stream.
+func formatAllocsPer10k(events []DBTraceEvent, totalOps int) string {
+	var totalAllocs uint64
+	for _, e := range events {
+		totalAllocs += e.Allocs
+	}
+
+	return fmt.Sprintf("%.1f", float64(totalAllocs)/float64(totalOps)*10000)
+}
+
+// maxOpIndex returns the highest OpIndex among events, or -1 if empty.
+func maxOpIndex(events []DBTraceEvent) int {
+	max := -1
+
+	for _, e := range events {
+		if e.OpIndex > max {
+			max = e.OpIndex
+		}
+	}
+
+	return max
+}
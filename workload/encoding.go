@@ -0,0 +1,504 @@
+package workload
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Workload wire formats. FormatJSONL is the default: one JSON object per
+// line, human-diffable and the format every existing vector/corpus file
+// uses. FormatMsgPack and FormatBinary trade that off for size and parse
+// speed once a workload runs into the multi-million-op regime, where JSONL's
+// per-op hex-string and object overhead starts to dominate generation and
+// load time.
+const (
+	FormatJSONL   = "jsonl"
+	FormatMsgPack = "msgpack"
+	FormatBinary  = "binary"
+)
+
+// msgpackMagic and binaryMagic prefix a file written in that format, so a
+// reader (see NewDecoder, and harness.Runner's use of SniffFormat) can tell
+// the three formats apart without relying on a file extension or an
+// out-of-band flag. JSONL needs no magic: it's whatever doesn't match
+// either prefix.
+var (
+	msgpackMagic = [4]byte{'S', 'T', 'M', 'P'}
+	binaryMagic  = [4]byte{'S', 'T', 'B', 'N'}
+)
+
+// SniffFormat identifies a workload's encoding from its leading bytes (e.g.
+// the result of a bufio.Reader.Peek(4)). Anything that doesn't match a
+// known magic prefix is assumed to be FormatJSONL.
+func SniffFormat(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, msgpackMagic[:]):
+		return FormatMsgPack
+	case bytes.HasPrefix(header, binaryMagic[:]):
+		return FormatBinary
+	default:
+		return FormatJSONL
+	}
+}
+
+// Encoder writes Operations to an underlying stream in some wire format.
+type Encoder interface {
+	Encode(op Operation) error
+}
+
+// NewEncoder returns an Encoder writing to w in the given format (one of
+// FormatJSONL, FormatMsgPack, FormatBinary; "" defaults to FormatJSONL). An
+// unrecognized format is an error rather than a silent fallback, so a typo
+// in --workload-format doesn't quietly produce a different corpus than the
+// caller asked for.
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case "", FormatJSONL:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+
+		return &jsonlEncoder{enc: enc}, nil
+
+	case FormatMsgPack:
+		if _, err := w.Write(msgpackMagic[:]); err != nil {
+			return nil, fmt.Errorf("write msgpack magic: %w", err)
+		}
+
+		return &msgpackEncoder{enc: msgpack.NewEncoder(w)}, nil
+
+	case FormatBinary:
+		if _, err := w.Write(binaryMagic[:]); err != nil {
+			return nil, fmt.Errorf("write binary magic: %w", err)
+		}
+
+		return &binaryEncoder{w: w}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown workload format %q", format)
+	}
+}
+
+// Decoder reads Operations back from a stream written by an Encoder (or,
+// for FormatJSONL, any hand-written JSONL workload file). Decode returns
+// io.EOF once the stream is exhausted.
+type Decoder interface {
+	Decode() (Operation, error)
+}
+
+// NewDecoder sniffs r's format from its leading bytes and returns a Decoder
+// for it, consuming the magic prefix (if any) in the process.
+func NewDecoder(r io.Reader) (Decoder, error) {
+	br := bufio.NewReaderSize(r, 1<<20)
+
+	header, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("peek workload header: %w", err)
+	}
+
+	switch SniffFormat(header) {
+	case FormatMsgPack:
+		if _, err := br.Discard(4); err != nil {
+			return nil, fmt.Errorf("discard msgpack magic: %w", err)
+		}
+
+		return &msgpackDecoder{dec: msgpack.NewDecoder(br)}, nil
+
+	case FormatBinary:
+		if _, err := br.Discard(4); err != nil {
+			return nil, fmt.Errorf("discard binary magic: %w", err)
+		}
+
+		return &binaryDecoder{r: br}, nil
+
+	default:
+		scanner := bufio.NewScanner(br)
+		scanner.Buffer(make([]byte, 0, 1<<20), 1<<20)
+
+		return &jsonlDecoder{scanner: scanner}, nil
+	}
+}
+
+type jsonlEncoder struct{ enc *json.Encoder }
+
+func (e *jsonlEncoder) Encode(op Operation) error { return e.enc.Encode(op) }
+
+type jsonlDecoder struct{ scanner *bufio.Scanner }
+
+func (d *jsonlDecoder) Decode() (Operation, error) {
+	if !d.scanner.Scan() {
+		if err := d.scanner.Err(); err != nil {
+			return Operation{}, fmt.Errorf("read workload: %w", err)
+		}
+
+		return Operation{}, io.EOF
+	}
+
+	var op Operation
+	if err := json.Unmarshal(d.scanner.Bytes(), &op); err != nil {
+		return Operation{}, fmt.Errorf("decode operation: %w", err)
+	}
+
+	return op, nil
+}
+
+type msgpackEncoder struct{ enc *msgpack.Encoder }
+
+func (e *msgpackEncoder) Encode(op Operation) error { return e.enc.Encode(op) }
+
+type msgpackDecoder struct{ dec *msgpack.Decoder }
+
+func (d *msgpackDecoder) Decode() (Operation, error) {
+	var op Operation
+	if err := d.dec.Decode(&op); err != nil {
+		return Operation{}, err // msgpack.Decoder.Decode returns io.EOF itself.
+	}
+
+	return op, nil
+}
+
+// binaryOpCodes maps an Operation's Op string to the single byte a binary
+// record identifies it by.
+var binaryOpCodes = map[string]byte{
+	"create_account":         1,
+	"set_code":               2,
+	"set_storage":            3,
+	OpExecuteTx:              4,
+	OpComputeRoot:            5,
+	OpReplay:                 6,
+	OpReuseDB:                7,
+	OpDeleteAccount:          8,
+	OpSelfdestructThenCreate: 9,
+	OpBeginBlock:             10,
+	OpEndBlock:               11,
+}
+
+var binaryOpNames = func() map[byte]string {
+	names := make(map[byte]string, len(binaryOpCodes))
+	for name, code := range binaryOpCodes {
+		names[code] = name
+	}
+
+	return names
+}()
+
+// Bit positions of Operation's optional fields within a binary record's
+// presence bitmask, in the order they're written when present.
+const (
+	bitAddress byte = 1 << iota
+	bitBalance
+	bitNonce
+	bitCode
+	bitSlot
+	bitValue
+	bitTo
+	bitCalldata
+)
+
+// binaryEncoder writes each Operation as a uint32-LE-length-prefixed
+// record: an op-code byte, a presence bitmask byte, then each present
+// field as fixed-width raw bytes (20 bytes for addresses, 32 for
+// hashes/balances, 8 for the nonce) or, for the two variable-length fields
+// (Code, Calldata), a uint32-LE length prefix followed by the raw bytes.
+// This keeps every fixed-width field at its natural size instead of paying
+// 2x+JSON overhead for its hex-string encoding.
+type binaryEncoder struct{ w io.Writer }
+
+func (e *binaryEncoder) Encode(op Operation) error {
+	rec, err := marshalBinary(op)
+	if err != nil {
+		return err
+	}
+
+	var lenBuf [4]byte
+	binary.LittleEndian.PutUint32(lenBuf[:], uint32(len(rec)))
+
+	if _, err := e.w.Write(lenBuf[:]); err != nil {
+		return fmt.Errorf("write record length: %w", err)
+	}
+
+	if _, err := e.w.Write(rec); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+
+	return nil
+}
+
+type binaryDecoder struct{ r io.Reader }
+
+func (d *binaryDecoder) Decode() (Operation, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(d.r, lenBuf[:]); err != nil {
+		if err == io.EOF {
+			return Operation{}, io.EOF
+		}
+
+		return Operation{}, fmt.Errorf("read record length: %w", err)
+	}
+
+	rec := make([]byte, binary.LittleEndian.Uint32(lenBuf[:]))
+	if _, err := io.ReadFull(d.r, rec); err != nil {
+		return Operation{}, fmt.Errorf("read record: %w", err)
+	}
+
+	return unmarshalBinary(rec)
+}
+
+func marshalBinary(op Operation) ([]byte, error) {
+	opCode, ok := binaryOpCodes[op.Op]
+	if !ok {
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+
+	var mask byte
+	for _, f := range []struct {
+		bit byte
+		set bool
+	}{
+		{bitAddress, op.Address != ""},
+		{bitBalance, op.Balance != ""},
+		{bitNonce, op.Nonce != 0},
+		{bitCode, op.Code != ""},
+		{bitSlot, op.Slot != ""},
+		{bitValue, op.Value != ""},
+		{bitTo, op.To != ""},
+		{bitCalldata, op.Calldata != ""},
+	} {
+		if f.set {
+			mask |= f.bit
+		}
+	}
+
+	buf := append([]byte{}, opCode, mask)
+
+	if mask&bitAddress != 0 {
+		b, err := hexToFixed(op.Address, 20)
+		if err != nil {
+			return nil, fmt.Errorf("address: %w", err)
+		}
+
+		buf = append(buf, b...)
+	}
+
+	if mask&bitBalance != 0 {
+		b, err := hexToFixed(op.Balance, 32)
+		if err != nil {
+			return nil, fmt.Errorf("balance: %w", err)
+		}
+
+		buf = append(buf, b...)
+	}
+
+	if mask&bitNonce != 0 {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], op.Nonce)
+		buf = append(buf, n[:]...)
+	}
+
+	if mask&bitCode != 0 {
+		b, err := hexToVar(op.Code)
+		if err != nil {
+			return nil, fmt.Errorf("code: %w", err)
+		}
+
+		buf = appendVar(buf, b)
+	}
+
+	if mask&bitSlot != 0 {
+		b, err := hexToFixed(op.Slot, 32)
+		if err != nil {
+			return nil, fmt.Errorf("slot: %w", err)
+		}
+
+		buf = append(buf, b...)
+	}
+
+	if mask&bitValue != 0 {
+		b, err := hexToFixed(op.Value, 32)
+		if err != nil {
+			return nil, fmt.Errorf("value: %w", err)
+		}
+
+		buf = append(buf, b...)
+	}
+
+	if mask&bitTo != 0 {
+		b, err := hexToFixed(op.To, 20)
+		if err != nil {
+			return nil, fmt.Errorf("to: %w", err)
+		}
+
+		buf = append(buf, b...)
+	}
+
+	if mask&bitCalldata != 0 {
+		b, err := hexToVar(op.Calldata)
+		if err != nil {
+			return nil, fmt.Errorf("calldata: %w", err)
+		}
+
+		buf = appendVar(buf, b)
+	}
+
+	return buf, nil
+}
+
+func unmarshalBinary(rec []byte) (Operation, error) {
+	if len(rec) < 2 {
+		return Operation{}, fmt.Errorf("binary record too short")
+	}
+
+	opName, ok := binaryOpNames[rec[0]]
+	if !ok {
+		return Operation{}, fmt.Errorf("unknown binary op code %d", rec[0])
+	}
+
+	mask := rec[1]
+	c := &byteCursor{b: rec[2:]}
+	op := Operation{Op: opName}
+
+	if mask&bitAddress != 0 {
+		b, err := c.take(20)
+		if err != nil {
+			return op, fmt.Errorf("address: %w", err)
+		}
+
+		op.Address = fixedToHex(b)
+	}
+
+	if mask&bitBalance != 0 {
+		b, err := c.take(32)
+		if err != nil {
+			return op, fmt.Errorf("balance: %w", err)
+		}
+
+		op.Balance = fixedToHex(b)
+	}
+
+	if mask&bitNonce != 0 {
+		b, err := c.take(8)
+		if err != nil {
+			return op, fmt.Errorf("nonce: %w", err)
+		}
+
+		op.Nonce = binary.BigEndian.Uint64(b)
+	}
+
+	if mask&bitCode != 0 {
+		b, err := c.takeVar()
+		if err != nil {
+			return op, fmt.Errorf("code: %w", err)
+		}
+
+		op.Code = fixedToHex(b)
+	}
+
+	if mask&bitSlot != 0 {
+		b, err := c.take(32)
+		if err != nil {
+			return op, fmt.Errorf("slot: %w", err)
+		}
+
+		op.Slot = fixedToHex(b)
+	}
+
+	if mask&bitValue != 0 {
+		b, err := c.take(32)
+		if err != nil {
+			return op, fmt.Errorf("value: %w", err)
+		}
+
+		op.Value = fixedToHex(b)
+	}
+
+	if mask&bitTo != 0 {
+		b, err := c.take(20)
+		if err != nil {
+			return op, fmt.Errorf("to: %w", err)
+		}
+
+		op.To = fixedToHex(b)
+	}
+
+	if mask&bitCalldata != 0 {
+		b, err := c.takeVar()
+		if err != nil {
+			return op, fmt.Errorf("calldata: %w", err)
+		}
+
+		op.Calldata = fixedToHex(b)
+	}
+
+	return op, nil
+}
+
+// byteCursor reads fixed- and variable-length fields off a binary record in
+// sequence, tracking position so each field's decode only needs to say how
+// much it wants.
+type byteCursor struct {
+	b   []byte
+	pos int
+}
+
+func (c *byteCursor) take(n int) ([]byte, error) {
+	if c.pos+n > len(c.b) {
+		return nil, fmt.Errorf("record truncated")
+	}
+
+	b := c.b[c.pos : c.pos+n]
+	c.pos += n
+
+	return b, nil
+}
+
+func (c *byteCursor) takeVar() ([]byte, error) {
+	lenBytes, err := c.take(4)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.take(int(binary.LittleEndian.Uint32(lenBytes)))
+}
+
+func appendVar(buf, b []byte) []byte {
+	var l [4]byte
+	binary.LittleEndian.PutUint32(l[:], uint32(len(b)))
+
+	return append(append(buf, l[:]...), b...)
+}
+
+// hexToFixed decodes a "0x"-prefixed hex string into exactly size bytes.
+// Callers only invoke it for a field the presence bitmask already marked
+// non-empty, so s is never "" here.
+func hexToFixed(s string, size int) ([]byte, error) {
+	b, err := hexToVar(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) != size {
+		return nil, fmt.Errorf("want %d bytes, got %d", size, len(b))
+	}
+
+	return b, nil
+}
+
+func hexToVar(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decode hex %q: %w", s, err)
+	}
+
+	return b, nil
+}
+
+func fixedToHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
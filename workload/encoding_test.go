@@ -0,0 +1,83 @@
+package workload
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	ops := []Operation{
+		{Op: "create_account", Address: "0x" + zeros(20), Balance: "0x" + zeros(32), Nonce: 7},
+		{Op: "set_code", Address: "0x" + zeros(20), Code: "0x6001600155"},
+		{Op: "set_storage", Address: "0x" + zeros(20), Slot: "0x" + zeros(32), Value: "0x" + zeros(32)},
+		{Op: OpExecuteTx, To: "0x" + zeros(20), Calldata: "0xabcdef"},
+		{Op: OpReuseDB},
+		{Op: OpComputeRoot},
+	}
+
+	for _, format := range []string{FormatJSONL, FormatMsgPack, FormatBinary} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			enc, err := NewEncoder(&buf, format)
+			if err != nil {
+				t.Fatalf("NewEncoder failed: %v", err)
+			}
+
+			for _, op := range ops {
+				if err := enc.Encode(op); err != nil {
+					t.Fatalf("Encode failed: %v", err)
+				}
+			}
+
+			dec, err := NewDecoder(&buf)
+			if err != nil {
+				t.Fatalf("NewDecoder failed: %v", err)
+			}
+
+			for i, want := range ops {
+				got, err := dec.Decode()
+				if err != nil {
+					t.Fatalf("Decode op %d failed: %v", i, err)
+				}
+
+				if got != want {
+					t.Errorf("op %d = %+v, want %+v", i, got, want)
+				}
+			}
+
+			if _, err := dec.Decode(); err != io.EOF {
+				t.Errorf("expected io.EOF after last op, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"msgpack magic", []byte("STMP..."), FormatMsgPack},
+		{"binary magic", []byte("STBN..."), FormatBinary},
+		{"jsonl object", []byte(`{"op":"x"}`), FormatJSONL},
+		{"empty", nil, FormatJSONL},
+	}
+
+	for _, tt := range tests {
+		if got := SniffFormat(tt.header); got != tt.want {
+			t.Errorf("%s: SniffFormat = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func zeros(n int) string {
+	b := make([]byte, n*2)
+	for i := range b {
+		b[i] = '0'
+	}
+
+	return string(b)
+}
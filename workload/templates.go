@@ -0,0 +1,65 @@
+package workload
+
+// Contract templates are small, hand-assembled EVM bytecode snippets used
+// to deploy contracts that exercise SSTORE/CREATE2 when called via
+// execute_tx, rather than writing storage slots directly. Each template
+// reads its argument from calldata offset 0.
+const (
+	templateERC20          = "erc20"
+	templateStorageWriter  = "storage_writer"
+	templateCREATE2Factory = "create2_factory"
+)
+
+var contractTemplates = []string{
+	templateERC20,
+	templateStorageWriter,
+	templateCREATE2Factory,
+}
+
+// templateCode returns the deployed bytecode for a contract template.
+func templateCode(name string) []byte {
+	switch name {
+	case templateERC20:
+		// Mimics a balance debit/credit pair: stores calldata[0:32] (amount)
+		// into slot 0 (sender balance) and slot 1 (recipient balance).
+		return []byte{
+			0x60, 0x00, // PUSH1 0x00
+			0x35,       // CALLDATALOAD      ; amount
+			0x60, 0x00, // PUSH1 0x00
+			0x55,       // SSTORE slot0 = amount
+			0x60, 0x00, // PUSH1 0x00
+			0x35,       // CALLDATALOAD      ; amount (again)
+			0x60, 0x01, // PUSH1 0x01
+			0x55, // SSTORE slot1 = amount
+			0x00, // STOP
+		}
+
+	case templateCREATE2Factory:
+		// Copies 1 byte of init code (STOP) into memory and deploys it via
+		// CREATE2, using calldata[0:32] as the salt.
+		return []byte{
+			0x60, 0x00, // PUSH1 0x00          ; init code: STOP
+			0x60, 0x00, // PUSH1 0x00
+			0x53,       // MSTORE8 mem[0] = 0x00
+			0x60, 0x00, // PUSH1 0x00
+			0x35,       // CALLDATALOAD        ; salt
+			0x60, 0x01, // PUSH1 0x01          ; size
+			0x60, 0x00, // PUSH1 0x00          ; offset
+			0x60, 0x00, // PUSH1 0x00          ; value
+			0xf5, // CREATE2
+			0x00, // STOP
+		}
+
+	default: // templateStorageWriter
+		// Stores calldata[0:32] into storage slot calldata[32:64].
+		return []byte{
+			0x60, 0x20, // PUSH1 0x20
+			0x35,       // CALLDATALOAD ; slot
+			0x60, 0x00, // PUSH1 0x00
+			0x35, // CALLDATALOAD ; value
+			0x90, // SWAP1
+			0x55, // SSTORE
+			0x00, // STOP
+		}
+	}
+}
@@ -1,26 +1,120 @@
 // Package workload generates deterministic JSONL workloads for Ethereum
 // state benchmarking. Each workload consists of create_account, set_code,
-// set_storage, and compute_root operations.
+// set_storage, optional execute_tx ops, and a terminal compute_root (or
+// replay) operation.
 package workload
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"io"
 	"math"
 	mrand "math/rand"
+	"strings"
 )
 
 // Operation represents a single state operation in the workload.
 type Operation struct {
-	Op      string `json:"op"`
-	Address string `json:"address,omitempty"`
-	Balance string `json:"balance,omitempty"`
-	Nonce   uint64 `json:"nonce,omitempty"`
-	Code    string `json:"code,omitempty"`
-	Slot    string `json:"slot,omitempty"`
-	Value   string `json:"value,omitempty"`
+	Op       string `json:"op"`
+	Address  string `json:"address,omitempty"`
+	Balance  string `json:"balance,omitempty"`
+	Nonce    uint64 `json:"nonce,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Slot     string `json:"slot,omitempty"`
+	Value    string `json:"value,omitempty"`
+	To       string `json:"to,omitempty"`
+	Calldata string `json:"calldata,omitempty"`
+}
+
+// Terminal op types. A workload always ends with one of these; which one
+// is emitted is controlled by Config.Mode and tells the harness whether
+// to run in plain aggregate mode or stream per-operation latencies.
+const (
+	OpComputeRoot = "compute_root"
+	OpReplay      = "replay"
+)
+
+// OpExecuteTx runs calldata against a deployed contract through the
+// client's EVM, rather than writing storage directly. This is how
+// set_storage/set_code changes arise in production.
+const OpExecuteTx = "execute_tx"
+
+// OpReuseDB, when it is the first op in a workload, tells the harness to
+// reopen the state already committed to its db dir (from a prior warmup
+// run) instead of starting from the empty root.
+const OpReuseDB = "reuse_db"
+
+// OpDeleteAccount and OpSelfdestructThenCreate let a workload express
+// account deletion and CREATE2 redeploy, which production state transitions
+// go through constantly but the original create/set-only op set couldn't
+// represent. A harness that tracks incarnation (see
+// harnesses/erigon/main.go) must bump the address's incarnation counter on
+// either op so a later create_account or the "then_create" half reuses a
+// storage key range no earlier incarnation occupied.
+const (
+	OpDeleteAccount          = "delete_account"
+	OpSelfdestructThenCreate = "selfdestruct_then_create"
+)
+
+// OpBeginBlock and OpEndBlock bracket the ops belonging to one block in a
+// multi-checkpoint workload. A compute_root between an end_block and the
+// next begin_block is a non-terminal checkpoint: the harness commits state,
+// reports the root for that block, and keeps processing the ops that
+// follow instead of exiting. Only a trailing compute_root with no further
+// ops, or a replay op, is terminal.
+const (
+	OpBeginBlock = "begin_block"
+	OpEndBlock   = "end_block"
+)
+
+// Mode selects the terminal op a generated workload ends with.
+const (
+	// ModeComputeRoot is the default: the workload ends in a single
+	// compute_root op and the harness reports aggregate timings only.
+	ModeComputeRoot = "compute_root"
+	// ModeReplay ends the workload in a replay op, signalling that the
+	// harness should additionally stream per-operation latencies and
+	// periodic memory snapshots as it processes the workload.
+	ModeReplay = "replay"
+)
+
+// Locality modes for KeyLocality and AddressLocality. The default ("" or
+// LocalityRandom) draws uniformly random 32-byte (keys) or 20-byte
+// (addresses) values, which produces a pessimal trie with no locality.
+const (
+	LocalityRandom     = "random"
+	LocalitySequential = "sequential"
+	LocalityZipf       = "zipf"
+	LocalityClustered  = "clustered"
+)
+
+// defaultZipfS is the Zipf-Mandelbrot exponent used when ZipfS is unset,
+// matching observed mainnet slot reuse.
+const defaultZipfS = 1.07
+
+// defaultNumClusters is the number of subtrie prefixes sampled when
+// NumClusters is unset.
+const defaultNumClusters = 16
+
+// defaultKeyPoolSize is the pool size zipf locality draws from when
+// KeyPoolSize is unset. 1 would collapse every zipf-distributed key or
+// address to the same value, defeating the point of the locality mode.
+const defaultKeyPoolSize = 10_000
+
+// VectorFormatVersion is the schema version written to every vector's
+// manifest sidecar file. Bump it whenever VectorManifest's shape changes so
+// consumers can reject vectors they don't know how to interpret.
+const VectorFormatVersion = 1
+
+// VectorManifest records a promoted workload's expected outcome and
+// preconditions, so a corpus of pinned test vectors can be replayed against
+// any harness.Runner and checked for state-root conformance.
+type VectorManifest struct {
+	Version           int    `json:"version"`
+	ExpectedStateRoot string `json:"expected_state_root"`
+	Accounts          int    `json:"accounts"`
+	Contracts         int    `json:"contracts"`
+	StorageSlots      int    `json:"storage_slots"`
 }
 
 // Summary contains statistics about the generated workload.
@@ -29,6 +123,8 @@ type Summary struct {
 	AccountsCreated  int
 	ContractsCreated int
 	StorageSlots     int
+	TxsExecuted      int
+	AccountsChurned  int
 }
 
 // Config controls workload generation parameters.
@@ -40,32 +136,97 @@ type Config struct {
 	Distribution string
 	Seed         int64
 	CodeSize     int
+	// Mode selects the terminal op (ModeComputeRoot by default). Set to
+	// ModeReplay to request per-operation latency profiling from the
+	// harness.
+	Mode string
+	// TxMix is the fraction (0.0-1.0) of a contract's slot writes that are
+	// issued as execute_tx ops against a deterministic contract template
+	// instead of direct set_storage ops. Zero (the default) preserves the
+	// original direct-write-only behavior.
+	TxMix float64
+	// KeyLocality controls how storage slot keys are generated: random
+	// (default, uniform), sequential, zipf, or clustered. See NewGenerator.
+	KeyLocality string
+	// AddressLocality does the same for account/contract addresses, using
+	// the same locality modes as KeyLocality.
+	AddressLocality string
+	// KeyPoolSize is the number of distinct values drawn from when
+	// KeyLocality or AddressLocality is "zipf". Ignored otherwise.
+	KeyPoolSize int
+	// ZipfS is the Zipf-Mandelbrot exponent used for "zipf" locality.
+	// Defaults to 1.07 (observed mainnet slot reuse) if unset.
+	ZipfS float64
+	// NumClusters is the number of random subtrie prefixes sampled for
+	// "clustered" locality. Defaults to 16 if unset.
+	NumClusters int
+	// ChurnMix is the fraction (0.0-1.0) of generated contracts that are
+	// deleted or redeployed (via delete_account or selfdestruct_then_create,
+	// split evenly) immediately after creation, exercising incarnation
+	// bumps and storage-range pruning (see harnesses/erigon/main.go). Zero
+	// (the default) emits neither op.
+	ChurnMix float64
 }
 
 // Generator produces deterministic workloads from a Config.
 type Generator struct {
 	cfg Config
 	rng *mrand.Rand
+
+	keyPool     [][]byte
+	keyZipf     *mrand.Zipf
+	keyClusters [][]byte
+	keySeq      uint64
+
+	addrPool     [][]byte
+	addrZipf     *mrand.Zipf
+	addrClusters [][]byte
+	addrSeq      uint64
 }
 
 // NewGenerator creates a Generator from the given Config.
 func NewGenerator(cfg Config) *Generator {
-	return &Generator{
-		cfg: cfg,
-		rng: mrand.New(mrand.NewSource(cfg.Seed)),
+	rng := mrand.New(mrand.NewSource(cfg.Seed))
+	g := &Generator{cfg: cfg, rng: rng}
+
+	switch cfg.KeyLocality {
+	case LocalityZipf:
+		g.keyPool = randomByteSlices(rng, poolSize(cfg.KeyPoolSize), 32)
+		g.keyZipf = newZipf(rng, cfg.ZipfS, len(g.keyPool))
+	case LocalityClustered:
+		g.keyClusters = randomByteSlices(rng, clusterCount(cfg.NumClusters), 8)
+	}
+
+	switch cfg.AddressLocality {
+	case LocalityZipf:
+		g.addrPool = randomByteSlices(rng, poolSize(cfg.KeyPoolSize), 20)
+		g.addrZipf = newZipf(rng, cfg.ZipfS, len(g.addrPool))
+	case LocalityClustered:
+		g.addrClusters = randomByteSlices(rng, clusterCount(cfg.NumClusters), 8)
 	}
+
+	return g
 }
 
-// Generate writes a JSONL workload to w and returns a Summary.
+// Generate writes a JSONL workload to w and returns a Summary. Use
+// GenerateFormat to write one of the more compact wire formats instead.
 func (g *Generator) Generate(w io.Writer) (Summary, error) {
-	enc := json.NewEncoder(w)
-	enc.SetEscapeHTML(false)
+	return g.GenerateFormat(w, FormatJSONL)
+}
+
+// GenerateFormat writes a workload to w in the given format (see
+// workload.Encoder) and returns a Summary.
+func (g *Generator) GenerateFormat(w io.Writer, format string) (Summary, error) {
+	enc, err := NewEncoder(w, format)
+	if err != nil {
+		return Summary{}, err
+	}
 
 	var summary Summary
 
 	// Generate EOAs.
 	for i := 0; i < g.cfg.NumAccounts; i++ {
-		addr := g.randomAddress()
+		addr := g.accountAddress()
 		balance := g.randomBalance(1, 100)
 		nonce := uint64(g.rng.Intn(100))
 
@@ -86,10 +247,17 @@ func (g *Generator) Generate(w io.Writer) (Summary, error) {
 	slotDist := g.slotDistribution()
 
 	for i := 0; i < g.cfg.NumContracts; i++ {
-		addr := g.randomAddress()
+		addr := g.accountAddress()
 		balance := g.randomBalance(0, 100)
 		nonce := uint64(g.rng.Intn(100))
+
+		numSlots := slotDist[i]
+		numTxs := int(float64(numSlots) * g.cfg.TxMix)
+
 		code := g.randomCode()
+		if numTxs > 0 {
+			code = g.templateCode()
+		}
 
 		if err := enc.Encode(Operation{
 			Op:      "create_account",
@@ -112,9 +280,8 @@ func (g *Generator) Generate(w io.Writer) (Summary, error) {
 
 		summary.TotalOperations++
 
-		numSlots := slotDist[i]
-		for j := 0; j < numSlots; j++ {
-			slot := g.randomHash()
+		for j := 0; j < numSlots-numTxs; j++ {
+			slot := g.slotKey()
 			value := g.randomNonZeroHash()
 
 			if err := enc.Encode(Operation{
@@ -130,12 +297,55 @@ func (g *Generator) Generate(w io.Writer) (Summary, error) {
 			summary.StorageSlots++
 		}
 
+		for j := 0; j < numTxs; j++ {
+			calldata := g.randomHash() + strings.TrimPrefix(g.randomHash(), "0x")
+
+			if err := enc.Encode(Operation{
+				Op:       OpExecuteTx,
+				To:       addr,
+				Calldata: calldata,
+			}); err != nil {
+				return summary, fmt.Errorf("encode execute_tx: %w", err)
+			}
+
+			summary.TotalOperations++
+			summary.TxsExecuted++
+		}
+
+		if g.cfg.ChurnMix > 0 && g.rng.Float64() < g.cfg.ChurnMix {
+			if g.rng.Intn(2) == 0 {
+				if err := enc.Encode(Operation{
+					Op:      OpDeleteAccount,
+					Address: addr,
+				}); err != nil {
+					return summary, fmt.Errorf("encode delete_account: %w", err)
+				}
+			} else {
+				if err := enc.Encode(Operation{
+					Op:      OpSelfdestructThenCreate,
+					Address: addr,
+					Balance: g.randomBalance(0, 100),
+					Nonce:   uint64(g.rng.Intn(100)),
+				}); err != nil {
+					return summary, fmt.Errorf("encode selfdestruct_then_create: %w", err)
+				}
+			}
+
+			summary.TotalOperations++
+			summary.AccountsChurned++
+		}
+
 		summary.ContractsCreated++
 	}
 
-	// Final compute_root operation.
-	if err := enc.Encode(Operation{Op: "compute_root"}); err != nil {
-		return summary, fmt.Errorf("encode compute_root: %w", err)
+	// Final terminal operation.
+	terminalOp := OpComputeRoot
+	if g.cfg.Mode == ModeReplay {
+		terminalOp = OpReplay
+	}
+
+	if err := enc.Encode(Operation{Op: terminalOp}); err != nil {
+		return summary, fmt.Errorf("encode %s: %w", terminalOp, err)
 	}
 
 	summary.TotalOperations++
@@ -143,6 +353,28 @@ func (g *Generator) Generate(w io.Writer) (Summary, error) {
 	return summary, nil
 }
 
+// WriteVector generates a workload to w, exactly like Generate, and returns
+// the VectorManifest describing it. Callers promote a deterministic
+// workload into a conformance corpus by writing w's contents to a
+// "<name>.jsonl" file and the returned manifest to a "<name>.manifest.json"
+// sidecar alongside it.
+func (g *Generator) WriteVector(
+	w io.Writer, expectedStateRoot string,
+) (VectorManifest, error) {
+	summary, err := g.Generate(w)
+	if err != nil {
+		return VectorManifest{}, err
+	}
+
+	return VectorManifest{
+		Version:           VectorFormatVersion,
+		ExpectedStateRoot: expectedStateRoot,
+		Accounts:          summary.AccountsCreated,
+		Contracts:         summary.ContractsCreated,
+		StorageSlots:      summary.StorageSlots,
+	}, nil
+}
+
 func (g *Generator) randomAddress() string {
 	var buf [20]byte
 	g.rng.Read(buf[:])
@@ -195,6 +427,105 @@ func (g *Generator) randomBalance(minETH, maxETH int) string {
 	return "0x" + hex.EncodeToString(buf[:])
 }
 
+// slotKey generates a storage slot key according to g.cfg.KeyLocality.
+func (g *Generator) slotKey() string {
+	switch g.cfg.KeyLocality {
+	case LocalitySequential:
+		g.keySeq++
+		return "0x" + hex.EncodeToString(seqBytes(g.keySeq, 32))
+	case LocalityZipf:
+		idx := g.keyZipf.Uint64()
+		return "0x" + hex.EncodeToString(g.keyPool[idx])
+	case LocalityClustered:
+		return "0x" + hex.EncodeToString(g.clusteredBytes(g.keyClusters, 32))
+	default:
+		return g.randomHash()
+	}
+}
+
+// accountAddress generates an account address according to
+// g.cfg.AddressLocality.
+func (g *Generator) accountAddress() string {
+	switch g.cfg.AddressLocality {
+	case LocalitySequential:
+		g.addrSeq++
+		return "0x" + hex.EncodeToString(seqBytes(g.addrSeq, 20))
+	case LocalityZipf:
+		idx := g.addrZipf.Uint64()
+		return "0x" + hex.EncodeToString(g.addrPool[idx])
+	case LocalityClustered:
+		return "0x" + hex.EncodeToString(g.clusteredBytes(g.addrClusters, 20))
+	default:
+		return g.randomAddress()
+	}
+}
+
+// clusteredBytes picks a random cluster prefix and fills the remaining
+// bytes with random suffix data, producing keys that share one of
+// len(clusters) raw prefixes. The keys' Keccak256 hashes, which are their
+// trie paths, do not share prefixes.
+func (g *Generator) clusteredBytes(clusters [][]byte, size int) []byte {
+	prefix := clusters[g.rng.Intn(len(clusters))]
+
+	buf := make([]byte, size)
+	copy(buf, prefix)
+	g.rng.Read(buf[len(prefix):])
+
+	return buf
+}
+
+// seqBytes encodes n as the trailing bytes of a size-byte big-endian
+// buffer, producing sequential keys/addresses.
+func seqBytes(n uint64, size int) []byte {
+	buf := make([]byte, size)
+
+	for i := size - 1; i >= 0 && n > 0; i-- {
+		buf[i] = byte(n)
+		n >>= 8
+	}
+
+	return buf
+}
+
+// randomByteSlices draws n independent random byte slices of the given
+// size, used to build the fixed pool a zipf distribution samples from.
+func randomByteSlices(rng *mrand.Rand, n, size int) [][]byte {
+	pool := make([][]byte, n)
+	for i := range pool {
+		buf := make([]byte, size)
+		rng.Read(buf)
+		pool[i] = buf
+	}
+
+	return pool
+}
+
+// newZipf builds a Zipf-Mandelbrot generator over a pool of the given
+// size, falling back to defaultZipfS when s is not set to a valid value.
+func newZipf(rng *mrand.Rand, s float64, poolSize int) *mrand.Zipf {
+	if s <= 1 {
+		s = defaultZipfS
+	}
+
+	return mrand.NewZipf(rng, s, 1, uint64(poolSize-1))
+}
+
+func poolSize(n int) int {
+	if n <= 0 {
+		return defaultKeyPoolSize
+	}
+
+	return n
+}
+
+func clusterCount(n int) int {
+	if n <= 0 {
+		return defaultNumClusters
+	}
+
+	return n
+}
+
 func (g *Generator) randomCode() string {
 	size := g.cfg.CodeSize + g.rng.Intn(g.cfg.CodeSize)
 	buf := make([]byte, size)
@@ -203,6 +534,14 @@ func (g *Generator) randomCode() string {
 	return "0x" + hex.EncodeToString(buf)
 }
 
+// templateCode picks a deterministic contract template by seed and
+// returns its deployed bytecode, hex-encoded.
+func (g *Generator) templateCode() string {
+	name := contractTemplates[g.rng.Intn(len(contractTemplates))]
+
+	return "0x" + hex.EncodeToString(templateCode(name))
+}
+
 func (g *Generator) slotDistribution() []int {
 	dist := make([]int, g.cfg.NumContracts)
 
@@ -210,6 +210,186 @@ func TestGenerateLastOpIsComputeRoot(t *testing.T) {
 	}
 }
 
+func TestGenerateReplayModeLastOp(t *testing.T) {
+	cfg := Config{
+		NumAccounts:  3,
+		NumContracts: 2,
+		MaxSlots:     3,
+		MinSlots:     1,
+		Distribution: "uniform",
+		Seed:         99,
+		CodeSize:     16,
+		Mode:         ModeReplay,
+	}
+
+	var buf bytes.Buffer
+	gen := NewGenerator(cfg)
+	if _, err := gen.Generate(&buf); err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) == 0 {
+		t.Fatal("empty output")
+	}
+
+	var lastOp Operation
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &lastOp); err != nil {
+		t.Fatalf("failed to parse last line: %v", err)
+	}
+
+	if lastOp.Op != OpReplay {
+		t.Errorf("last op = %q, want %q", lastOp.Op, OpReplay)
+	}
+}
+
+func TestGenerateTxMix(t *testing.T) {
+	cfg := Config{
+		NumAccounts:  0,
+		NumContracts: 5,
+		MaxSlots:     10,
+		MinSlots:     10,
+		Distribution: "uniform",
+		Seed:         7,
+		CodeSize:     32,
+		TxMix:        0.5,
+	}
+
+	var buf bytes.Buffer
+	gen := NewGenerator(cfg)
+
+	sum, err := gen.Generate(&buf)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	if sum.TxsExecuted == 0 {
+		t.Error("expected some execute_tx ops with TxMix > 0")
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var op Operation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		if op.Op != OpExecuteTx {
+			continue
+		}
+
+		if !strings.HasPrefix(op.To, "0x") {
+			t.Errorf("execute_tx missing to address: %+v", op)
+		}
+		if !strings.HasPrefix(op.Calldata, "0x") {
+			t.Errorf("execute_tx missing calldata: %+v", op)
+		}
+	}
+}
+
+func TestGenerateChurnMix(t *testing.T) {
+	cfg := Config{
+		NumAccounts:  0,
+		NumContracts: 20,
+		MaxSlots:     4,
+		MinSlots:     4,
+		Distribution: "uniform",
+		Seed:         7,
+		CodeSize:     32,
+		ChurnMix:     1.0,
+	}
+
+	var buf bytes.Buffer
+	gen := NewGenerator(cfg)
+
+	sum, err := gen.Generate(&buf)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	if sum.AccountsChurned != cfg.NumContracts {
+		t.Errorf("AccountsChurned = %d, want %d (ChurnMix 1.0)", sum.AccountsChurned, cfg.NumContracts)
+	}
+
+	var deletes, redeploys int
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var op Operation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		switch op.Op {
+		case OpDeleteAccount:
+			deletes++
+			if !strings.HasPrefix(op.Address, "0x") {
+				t.Errorf("delete_account missing address: %+v", op)
+			}
+		case OpSelfdestructThenCreate:
+			redeploys++
+			if !strings.HasPrefix(op.Address, "0x") {
+				t.Errorf("selfdestruct_then_create missing address: %+v", op)
+			}
+		}
+	}
+
+	if deletes+redeploys != cfg.NumContracts {
+		t.Errorf("delete_account+selfdestruct_then_create = %d, want %d", deletes+redeploys, cfg.NumContracts)
+	}
+	if deletes == 0 || redeploys == 0 {
+		t.Errorf("expected both op kinds across %d contracts, got %d delete_account, %d selfdestruct_then_create", cfg.NumContracts, deletes, redeploys)
+	}
+}
+
+func TestKeyLocality(t *testing.T) {
+	for _, locality := range []string{"sequential", "zipf", "clustered"} {
+		t.Run(locality, func(t *testing.T) {
+			cfg := Config{
+				NumAccounts:     0,
+				NumContracts:    5,
+				MaxSlots:        20,
+				MinSlots:        20,
+				Distribution:    "uniform",
+				Seed:            7,
+				CodeSize:        16,
+				KeyLocality:     locality,
+				AddressLocality: locality,
+				KeyPoolSize:     8,
+				NumClusters:     4,
+			}
+
+			var buf bytes.Buffer
+			gen := NewGenerator(cfg)
+
+			sum, err := gen.Generate(&buf)
+			if err != nil {
+				t.Fatalf("generation failed: %v", err)
+			}
+
+			if sum.StorageSlots == 0 {
+				t.Error("expected some storage slots")
+			}
+
+			scanner := bufio.NewScanner(&buf)
+			for scanner.Scan() {
+				var op Operation
+				if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+					t.Fatalf("invalid JSON: %v", err)
+				}
+
+				if op.Op == "set_storage" && !strings.HasPrefix(op.Slot, "0x") {
+					t.Errorf("slot missing 0x prefix: %+v", op)
+				}
+				if op.Op == "create_account" &&
+					!strings.HasPrefix(op.Address, "0x") {
+					t.Errorf("address missing 0x prefix: %+v", op)
+				}
+			}
+		})
+	}
+}
+
 func TestDistributions(t *testing.T) {
 	for _, dist := range []string{"power-law", "exponential", "uniform"} {
 		t.Run(dist, func(t *testing.T) {
@@ -240,3 +420,41 @@ func TestDistributions(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteVector(t *testing.T) {
+	cfg := Config{
+		NumAccounts:  4,
+		NumContracts: 2,
+		MaxSlots:     5,
+		MinSlots:     1,
+		Distribution: "uniform",
+		Seed:         7,
+		CodeSize:     16,
+	}
+
+	var buf bytes.Buffer
+	gen := NewGenerator(cfg)
+
+	manifest, err := gen.WriteVector(&buf, "0xdeadbeef")
+	if err != nil {
+		t.Fatalf("WriteVector failed: %v", err)
+	}
+
+	if manifest.Version != VectorFormatVersion {
+		t.Errorf("version = %d, want %d", manifest.Version, VectorFormatVersion)
+	}
+	if manifest.ExpectedStateRoot != "0xdeadbeef" {
+		t.Errorf("expected root = %q, want 0xdeadbeef", manifest.ExpectedStateRoot)
+	}
+	if manifest.Accounts != cfg.NumAccounts {
+		t.Errorf("accounts = %d, want %d", manifest.Accounts, cfg.NumAccounts)
+	}
+	if manifest.Contracts != cfg.NumContracts {
+		t.Errorf("contracts = %d, want %d", manifest.Contracts, cfg.NumContracts)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) == 0 {
+		t.Fatal("empty workload output")
+	}
+}